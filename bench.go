@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"./packing"
+)
+
+// Итоги запусков одного алгоритма на одной задаче
+type benchResult struct {
+	instance  string
+	algorithm string
+	// target - оптимальное или лучшее известное число
+	// контейнеров, а если оно неизвестно - нижняя оценка
+	target int
+	known  bool
+	runs   int
+	// best и total - наименьшее и суммарное число контейнеров
+	best  int
+	total int
+	// successes - число корректных решений с target контейнерами
+	successes int
+	invalid   int
+	elapsed   time.Duration
+}
+
+// Среднее число контейнеров
+func (b benchResult) meanBins() float64 {
+	return float64(b.total) / float64(b.runs)
+}
+
+// Среднее отклонение числа контейнеров от target
+func (b benchResult) gap() float64 {
+	return b.meanBins() - float64(b.target)
+}
+
+// Доля запусков, в которых достигнуто target
+func (b benchResult) successRate() float64 {
+	return float64(b.successes) / float64(b.runs)
+}
+
+// Среднее время одного запуска в миллисекундах
+func (b benchResult) meanElapsed() float64 {
+	return float64(b.elapsed) / float64(b.runs) / float64(time.Millisecond)
+}
+
+// Выполняет чтение всех задач из файлов каталога (кроме
+// вложенных каталогов и скрытых файлов) в порядке имён файлов
+func readSuite(dir string, format string) ([]instance, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var instances []instance
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		result, err := readInstances(filename, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		if err := checkInstances(result); err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		instances = append(instances, result...)
+	}
+	return instances, nil
+}
+
+/*
+	Сравнение алгоритмов на наборе задач
+	входные данные:
+		ctx - контекст, при отмене которого сравнение прерывается
+		instances - задачи
+		algorithms - названия алгоритмов (см. runAlgorithm)
+		seeds - число запусков отжига с зёрнами seed, seed+1, ...;
+		        остальные алгоритмы детерминированы и запускаются один раз
+		param - параметры отжига
+		timeLimit - ограничение времени одного запуска
+	выходные данные:
+		итоги для каждой пары задачи и алгоритма; при прерывании -
+		итоги завершённых пар и ошибка контекста
+*/
+func runBench(ctx context.Context, instances []instance, algorithms []string, seeds int, seed int64,
+	param packing.AnnealOptions, timeLimit time.Duration) ([]benchResult, error) {
+	var results []benchResult
+	for _, inst := range instances {
+		weights := itemWeights(inst.items)
+		target, known := inst.best, inst.best > 0
+		if !known {
+			target = packing.LowerBound(weights, inst.capacity).Best()
+		}
+
+		for _, algorithm := range algorithms {
+			result := benchResult{instance: inst.name, algorithm: algorithm, target: target, known: known}
+			runs := 1
+			if algorithm == "anneal" {
+				runs = seeds
+			}
+			for i := 0; i < runs; i++ {
+				options := param
+				options.Seed = seed + int64(i)
+				options.TimeLimit = timeLimit
+				r, err := runAlgorithm(ctx, algorithm, inst, options, timeLimit)
+				if err != nil {
+					return results, err
+				}
+
+				bins := r.solution.NumBins()
+				valid := packing.ValidateItems(inst.items, inst.capacity, r.solution.Containers) == nil
+				if result.runs == 0 || bins < result.best {
+					result.best = bins
+				}
+				result.runs++
+				result.total += bins
+				result.elapsed += r.elapsed
+				switch {
+				case !valid:
+					result.invalid++
+				case bins <= target:
+					result.successes++
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// Заголовок таблицы сравнения в формате CSV
+var benchHeader = []string{"instance", "algorithm", "runs", "target", "target_known",
+	"best_bins", "mean_bins", "gap", "mean_elapsed_ms", "success_rate", "invalid"}
+
+// Записывает итоги сравнения в формате CSV
+func writeBenchCSV(writer io.Writer, results []benchResult) error {
+	table := csv.NewWriter(writer)
+	if err := table.Write(benchHeader); err != nil {
+		return err
+	}
+	for _, b := range results {
+		record := []string{
+			b.instance,
+			b.algorithm,
+			strconv.Itoa(b.runs),
+			strconv.Itoa(b.target),
+			strconv.FormatBool(b.known),
+			strconv.Itoa(b.best),
+			strconv.FormatFloat(b.meanBins(), 'f', 2, 64),
+			strconv.FormatFloat(b.gap(), 'f', 2, 64),
+			strconv.FormatFloat(b.meanElapsed(), 'f', 3, 64),
+			strconv.FormatFloat(b.successRate(), 'f', 4, 64),
+			strconv.Itoa(b.invalid),
+		}
+		if err := table.Write(record); err != nil {
+			return err
+		}
+	}
+	table.Flush()
+	return table.Error()
+}
+
+// Записывает итоги сравнения в виде таблиц Markdown: по каждой
+// задаче и сводную по алгоритмам. Цель (*) - оптимальное или
+// лучшее известное число контейнеров, иначе нижняя оценка
+func writeBenchMarkdown(writer io.Writer, results []benchResult) error {
+	var b strings.Builder
+	b.WriteString("| Задача | Алгоритм | Запуски | Цель | Лучшее | Среднее | Отклонение | Время, мс | Успех |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, r := range results {
+		target := strconv.Itoa(r.target)
+		if r.known {
+			target += "*"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %d | %.2f | %.2f | %.3f | %.0f%% |\n", r.instance, r.algorithm,
+			r.runs, target, r.best, r.meanBins(), r.gap(), r.meanElapsed(), r.successRate()*100)
+	}
+
+	// сводная таблица в порядке первого появления алгоритмов
+	var algorithms []string
+	summary := map[string]*benchResult{}
+	gaps := map[string]float64{}
+	instances := map[string]int{}
+	for _, r := range results {
+		total, ok := summary[r.algorithm]
+		if !ok {
+			algorithms = append(algorithms, r.algorithm)
+			total = &benchResult{algorithm: r.algorithm}
+			summary[r.algorithm] = total
+		}
+		total.runs += r.runs
+		total.successes += r.successes
+		total.invalid += r.invalid
+		total.elapsed += r.elapsed
+		gaps[r.algorithm] += r.gap()
+		instances[r.algorithm]++
+	}
+	b.WriteString("\n| Алгоритм | Задачи | Среднее отклонение | Время, мс | Успех | Некорректные |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+	for _, algorithm := range algorithms {
+		total := summary[algorithm]
+		fmt.Fprintf(&b, "| %s | %d | %.2f | %.3f | %.0f%% | %d |\n", algorithm, instances[algorithm],
+			gaps[algorithm]/float64(instances[algorithm]), total.meanElapsed(), total.successRate()*100, total.invalid)
+	}
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// Подкоманда bench: сравнение алгоритмов на всех задачах каталога
+func benchCommand(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: bench [флаги] каталог")
+		flags.PrintDefaults()
+	}
+	algos := flags.String("algos", "bestfit,ffd,bfd,anneal", "алгоритмы через запятую (как в -algo)")
+	seeds := flags.Int("seeds", 5, "число запусков отжига с разными зёрнами")
+	seed := flags.Int64("seed", 1, "зерно генератора случайных чисел первого запуска")
+	timeLimit := flags.Duration("time", 0, "ограничение времени одного запуска, например 10s (0 - без ограничения)")
+	inputFormat := flags.String("input", "auto", "формат входных файлов: "+strings.Join(inputFormats, ", "))
+	csvFile := flags.String("csv", "", "файл для таблицы в формате CSV")
+	mdFile := flags.String("md", "", "файл для таблицы в формате Markdown (по умолчанию - стандартный вывод)")
+	param := packing.AnnealOptions{}
+	flags.Float64Var(&param.Temperature, "T", 1000, "начальная температура")
+	flags.Float64Var(&param.CoolingRate, "r", 0.8, "коэффициент охлаждения")
+	flags.IntVar(&param.Steps, "L", 100, "число шагов алгоритма при одной температуре")
+	flags.IntVar(&param.StagnationLimit, "E", 5, "число смен температуры без изменения решения до остановки")
+	anneal := addAnnealFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("необходимо указание каталога с задачами")
+	}
+	algorithms := strings.Split(*algos, ",")
+	for i, algorithm := range algorithms {
+		algorithms[i] = strings.TrimSpace(algorithm)
+		if _, ok := heuristics[algorithms[i]]; !ok && algorithms[i] != "anneal" && algorithms[i] != "exact" {
+			return fmt.Errorf("неизвестный алгоритм: %q", algorithms[i])
+		}
+	}
+	if *seeds < 1 {
+		return fmt.Errorf("число запусков должно быть положительным: %d", *seeds)
+	}
+	param, err := anneal.options(param)
+	if err != nil {
+		return err
+	}
+
+	instances, err := readSuite(flags.Arg(0), *inputFormat)
+	if err != nil {
+		return err
+	}
+
+	// по Ctrl+C выводятся итоги уже завершённых запусков
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results, benchErr := runBench(ctx, instances, algorithms, *seeds, *seed, param, *timeLimit)
+
+	if *csvFile != "" {
+		file, err := os.Create(*csvFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := writeBenchCSV(file, results); err != nil {
+			return err
+		}
+	}
+	output := io.Writer(os.Stdout)
+	if *mdFile != "" {
+		file, err := os.Create(*mdFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+	if err := writeBenchMarkdown(output, results); err != nil {
+		return err
+	}
+	return benchErr
+}
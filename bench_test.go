@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"./packing"
+)
+
+func TestReadSuite(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":   "10\n3\n4\n6\n5\n",
+		"b.json":  `{"name": "b", "capacity": 10, "items": [{"id": 1, "weight": 7}]}`,
+		".hidden": "garbage",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	instances, err := readSuite(dir, "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[0].name != "a" || len(instances[0].items) != 3 || instances[1].name != "b" {
+		t.Error("result:", instances)
+	}
+
+	// предмет тяжелее вместимости
+	if err := os.WriteFile(filepath.Join(dir, "c.txt"), []byte("10\n1\n11\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readSuite(dir, "auto"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestRunBench(t *testing.T) {
+	instances := []instance{
+		// известен оптимум: 6 + 4, 5 + 5
+		instance{ name: "known", capacity: 10, items: packing.NewItems([]int{ 6, 5, 4, 5 }), best: 2 },
+		// оптимум неизвестен, цель - нижняя оценка
+		instance{ name: "bound", capacity: 10, items: packing.NewItems([]int{ 4, 4, 4 }) },
+	}
+	param := packing.AnnealOptions{ Temperature: 1, CoolingRate: 0.5, Steps: 10, StagnationLimit: 2 }
+	results, err := runBench(context.Background(), instances, []string{ "nf", "anneal" }, 3, 1, param, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples := []struct {
+		instance string
+		algorithm string
+		runs int
+		target int
+		best int
+		successes int
+	}{
+		{ "known", "nf", 1, 2, 3, 0 },
+		{ "known", "anneal", 3, 2, 2, 3 },
+		{ "bound", "nf", 1, 2, 2, 1 },
+		{ "bound", "anneal", 3, 2, 2, 3 },
+	}
+	if len(results) != len(samples) {
+		t.Fatal("result:", results)
+	}
+	for i, sample := range samples {
+		r := results[i]
+		if r.instance != sample.instance || r.algorithm != sample.algorithm || r.runs != sample.runs ||
+			r.target != sample.target || r.best != sample.best || r.successes != sample.successes || r.invalid != 0 {
+			t.Error("result:", r, "| expected:", sample)
+		}
+	}
+
+	// прерванное сравнение возвращает ошибку контекста
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := runBench(ctx, instances, []string{ "anneal" }, 1, 1, param, 0); err != context.Canceled {
+		t.Error("error:", err, "| expected:", context.Canceled)
+	}
+}
+
+func TestWriteBench(t *testing.T) {
+	results := []benchResult{
+		benchResult{ instance: "a", algorithm: "anneal", target: 2, known: true, runs: 4, best: 2, total: 10, successes: 2 },
+		benchResult{ instance: "b", algorithm: "anneal", target: 3, runs: 2, best: 3, total: 6, successes: 2 },
+	}
+
+	var buffer bytes.Buffer
+	if err := writeBenchCSV(&buffer, results); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{ "a", "anneal", "4", "2", "true", "2", "2.50", "0.50", "0.000", "0.5000", "0" }
+	if len(records) != 3 || strings.Join(records[1], ",") != strings.Join(expected, ",") {
+		t.Error("result:", records, "| expected:", expected)
+	}
+
+	buffer.Reset()
+	if err := writeBenchMarkdown(&buffer, results); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []string{
+		"| a | anneal | 4 | 2* | 2 | 2.50 | 0.50 | 0.000 | 50% |",
+		"| b | anneal | 2 | 3 | 3 | 3.00 | 0.00 | 0.000 | 100% |",
+		"| anneal | 2 | 0.25 | 0.000 | 67% | 0 |",
+	} {
+		if !strings.Contains(buffer.String(), row) {
+			t.Error("missing row:", row, "| result:\n"+buffer.String())
+		}
+	}
+}
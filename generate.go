@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Семейство случайных задач: значения по умолчанию для числа
+// предметов, вместимости и диапазона весов
+type family struct {
+	n        int
+	capacity int
+	min, max int
+	// triplets - задача составляется из троек предметов,
+	// заполняющих контейнер полностью; min и max не используются
+	triplets bool
+}
+
+// Классические семейства задач
+var families = map[string]family{
+	// Фалькенауэр, класс u: равномерные веса
+	"uniform": family{n: 120, capacity: 150, min: 20, max: 100},
+	// Фалькенауэр, класс t: тройки с известным оптимумом n/3
+	"triplets": family{n: 60, capacity: 1000, triplets: true},
+	// Швэрин и Вэшер: узкий диапазон весов, 5-6 предметов в контейнере
+	"sw": family{n: 100, capacity: 1000, min: 150, max: 200},
+	// трудные задачи в духе Hard28 и набора Шолла 3:
+	// 3-5 предметов в контейнере при большой вместимости
+	"hard": family{n: 200, capacity: 100000, min: 20000, max: 35000},
+}
+
+// Возвращает n весов, равномерно распределённых на [min, max]
+func uniformWeights(n, min, max int, rng *rand.Rand) []int {
+	weights := make([]int, n)
+	for i := range weights {
+		weights[i] = min + rng.Intn(max-min+1)
+	}
+	return weights
+}
+
+/*
+	Генерация троек Фалькенауэра
+	входные данные:
+		m - число троек (контейнеров оптимального решения)
+		capacity - вместимость контейнеров
+		rng - источник случайных чисел
+	выходные данные:
+		3m весов в случайном порядке: в каждой тройке первый вес
+		равномерно распределён на [0.38W, 0.49W], второй - на
+		[0.25W, s/2], где s - остаток после первого, третий
+		дополняет контейнер до полного; поэтому оптимальное
+		решение состоит ровно из m контейнеров
+*/
+func tripletWeights(m, capacity int, rng *rand.Rand) []int {
+	weights := make([]int, 0, 3*m)
+	for i := 0; i < m; i++ {
+		first := uniformWeights(1, capacity*38/100, capacity*49/100, rng)[0]
+		rest := capacity - first
+		second := uniformWeights(1, capacity/4, rest/2, rng)[0]
+		weights = append(weights, first, second, rest-second)
+	}
+	rng.Shuffle(len(weights), func(i, j int) {
+		weights[i], weights[j] = weights[j], weights[i]
+	})
+	return weights
+}
+
+// Возвращает диапазон весов семейства f,
+// масштабированный под заданную вместимость
+func (f family) bounds(capacity int) (int, int) {
+	return f.min * capacity / f.capacity, f.max * capacity / f.capacity
+}
+
+// Подкоманда generate: вывод случайной задачи в формате readData
+func generateCommand(args []string) error {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	name := flags.String("family", "uniform", "семейство задач: "+strings.Join(names, ", "))
+	n := flags.Int("n", 0, "число предметов; для triplets - кратное 3 (0 - по умолчанию для семейства)")
+	capacity := flags.Int("W", 0, "вместимость контейнеров (0 - по умолчанию для семейства)")
+	min := flags.Int("min", 0, "наименьший вес, кроме triplets (0 - по умолчанию для семейства)")
+	max := flags.Int("max", 0, "наибольший вес, кроме triplets (0 - по умолчанию для семейства)")
+	seed := flags.Int64("seed", 0, "зерно генератора случайных чисел (по умолчанию - текущее время)")
+	output := flags.String("o", "", "выходной файл (по умолчанию - стандартный вывод)")
+	flags.Parse(args)
+
+	f, ok := families[*name]
+	if !ok {
+		return fmt.Errorf("неизвестное семейство задач: %q", *name)
+	}
+	if *n == 0 {
+		*n = f.n
+	}
+	if *capacity == 0 {
+		*capacity = f.capacity
+	}
+	lo, hi := f.bounds(*capacity)
+	if *min > 0 {
+		lo = *min
+	}
+	if *max > 0 {
+		hi = *max
+	}
+	switch {
+	case *n < 0 || (f.triplets && *n%3 != 0):
+		return fmt.Errorf("неверное число предметов: %d", *n)
+	case *capacity < 4:
+		return fmt.Errorf("неверная вместимость: %d", *capacity)
+	case !f.triplets && (lo < 1 || lo > hi || hi > *capacity):
+		return fmt.Errorf("неверный диапазон весов: [%d, %d] при вместимости %d", lo, hi, *capacity)
+	}
+
+	set := false
+	flags.Visit(func(flag *flag.Flag) {
+		set = set || flag.Name == "seed"
+	})
+	if !set {
+		*seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(*seed))
+	var weights []int
+	if f.triplets {
+		weights = tripletWeights(*n/3, *capacity, rng)
+	} else {
+		weights = uniformWeights(*n, lo, hi, rng)
+	}
+
+	writer := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+	// зерно выводится, чтобы задачу можно было воспроизвести
+	fmt.Fprintln(os.Stderr, "Зерно генератора случайных чисел -", *seed)
+	if f.triplets {
+		fmt.Fprintln(os.Stderr, "Оптимальное число контейнеров -", *n/3)
+	}
+	return writeData(writer, weights, *capacity)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUniformWeights(t *testing.T) {
+	for name, f := range families {
+		if f.triplets {
+			continue
+		}
+		lo, hi := f.bounds(f.capacity)
+		weights := uniformWeights(f.n, lo, hi, rand.New(rand.NewSource(1)))
+		if len(weights) != f.n {
+			t.Fatal(name, "result:", weights)
+		}
+		for _, weight := range weights {
+			if weight < f.min || weight > f.max {
+				t.Fatal(name, "weight:", weight, "| expected range:", f.min, f.max)
+			}
+		}
+
+		// одинаковое зерно даёт одинаковую задачу
+		again := uniformWeights(f.n, lo, hi, rand.New(rand.NewSource(1)))
+		if fmt.Sprint(again) != fmt.Sprint(weights) {
+			t.Error(name, "result:", again, "| expected:", weights)
+		}
+	}
+
+	// диапазон масштабируется вместе с вместимостью
+	if lo, hi := families["sw"].bounds(2000); lo != 300 || hi != 400 {
+		t.Error("bounds:", lo, hi, "| expected:", 300, 400)
+	}
+}
+
+func TestTripletWeights(t *testing.T) {
+	for _, capacity := range []int{ 4, 100, 1000 } {
+		weights := tripletWeights(20, capacity, rand.New(rand.NewSource(2)))
+		if len(weights) != 60 {
+			t.Fatal("result:", weights)
+		}
+		// контейнеры оптимального решения заполнены полностью,
+		// при этом в контейнер помещается не больше трёх предметов
+		sum := 0
+		for _, weight := range weights {
+			if weight < 1 || 4*weight < capacity || 2*weight > capacity {
+				t.Fatal("capacity:", capacity, "| weight:", weight)
+			}
+			sum += weight
+		}
+		if sum != 20*capacity {
+			t.Error("capacity:", capacity, "| sum:", sum, "| expected:", 20*capacity)
+		}
+	}
+}
+
+func TestWriteData(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "instance.txt")
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := []int{ 42, 69, 67 }
+	if err := writeData(file, weights, 150); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	result, capacity, err := readData(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capacity != 150 || fmt.Sprint(result) != fmt.Sprint(weights) {
+		t.Error("result:", result, capacity, "| expected:", weights, 150)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"./packing"
+)
+
+// Предмет задачи в формате JSON
+type jsonItem struct {
+	ID      int             `json:"id"`
+	Weight  int             `json:"weight"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Задача в формате JSON:
+//
+//	{
+//		"name": "example",
+//		"capacity": 150,
+//		"items": [{"id": 0, "weight": 42}, {"id": 1, "weight": 69, "payload": {...}}],
+//		"best_known": 48,
+//		"metadata": {...}
+//	}
+//
+// поля name, payload, best_known и metadata необязательны
+type jsonInstance struct {
+	Name      string          `json:"name,omitempty"`
+	Capacity  int             `json:"capacity"`
+	Items     []jsonItem      `json:"items"`
+	BestKnown int             `json:"best_known,omitempty"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Разбирает задачу в формате JSON; идентификаторы
+// предметов должны быть уникальными
+func decodeInstance(reader io.Reader) (instance, error) {
+	var input jsonInstance
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		return instance{}, err
+	}
+
+	items := make([]packing.Item, len(input.Items))
+	ids := map[int]bool{}
+	for i, item := range input.Items {
+		if ids[item.ID] {
+			return instance{}, fmt.Errorf("повторяющийся идентификатор предмета: %d", item.ID)
+		}
+		ids[item.ID] = true
+		items[i] = packing.Item{ID: item.ID, Weight: item.Weight}
+		if item.Payload != nil {
+			items[i].Payload = item.Payload
+		}
+	}
+	return instance{name: input.Name, capacity: input.Capacity, items: items, best: input.BestKnown}, nil
+}
+
+// Контейнер решения в формате JSON
+type jsonBin struct {
+	Load  int        `json:"load"`
+	Items []jsonItem `json:"items"`
+}
+
+// Параметры отжига в формате JSON
+type jsonParams struct {
+	T    float64 `json:"T"`
+	R    float64 `json:"r"`
+	L    int     `json:"L"`
+	E    int     `json:"E"`
+	Seed int64   `json:"seed"`
+}
+
+// Показатели работы отжига в формате JSON
+type jsonAnnealStats struct {
+	Temperature float64 `json:"temperature"`
+	Energy      float64 `json:"energy"`
+	BestEpoch   int     `json:"best_epoch"`
+	Iterations  int     `json:"iterations"`
+	Epochs      int     `json:"epochs"`
+	Accepted    int     `json:"accepted"`
+	Rejected    int     `json:"rejected"`
+	Reheats     int     `json:"reheats"`
+}
+
+// Показатели работы точного алгоритма в формате JSON
+type jsonExactStats struct {
+	LowerBound int  `json:"lower_bound"`
+	Optimal    bool `json:"optimal"`
+	Nodes      int  `json:"nodes"`
+}
+
+// Результат одного запуска в формате JSON
+type jsonRun struct {
+	Algorithm  string           `json:"algorithm"`
+	Run        int              `json:"run"`
+	Params     *jsonParams      `json:"params,omitempty"`
+	NumBins    int              `json:"num_bins"`
+	LowerBound int              `json:"lower_bound"`
+	Gap        int              `json:"gap"`
+	Fill       float64          `json:"fill"`
+	ElapsedMS  float64          `json:"elapsed_ms"`
+	Valid      bool             `json:"valid"`
+	Anneal     *jsonAnnealStats `json:"anneal,omitempty"`
+	Exact      *jsonExactStats  `json:"exact,omitempty"`
+	Bins       []jsonBin        `json:"bins"`
+}
+
+// Результаты всех запусков для одной задачи в формате JSON
+type jsonSolution struct {
+	Name       string    `json:"name,omitempty"`
+	BestKnown  int       `json:"best_known,omitempty"`
+	Capacity   int       `json:"capacity"`
+	Items      int       `json:"items"`
+	LowerBound int       `json:"lower_bound"`
+	Runs       []jsonRun `json:"runs"`
+}
+
+// Возвращает результат запуска в формате JSON
+func newJSONRun(r report, items []packing.Item, bounds packing.Bounds) jsonRun {
+	solution := r.solution
+	run := jsonRun{
+		Algorithm:  r.algorithm,
+		Run:        r.run,
+		NumBins:    solution.NumBins(),
+		LowerBound: bounds.Best(),
+		Gap:        bounds.Gap(solution),
+		Fill:       solution.FillRatio(),
+		ElapsedMS:  float64(r.elapsed) / float64(time.Millisecond),
+		Valid:      packing.ValidateItems(items, solution.Capacity, solution.Containers) == nil,
+		Bins:       make([]jsonBin, len(solution.Containers)),
+	}
+	if r.params != nil {
+		run.Params = &jsonParams{T: r.params.Temperature, R: r.params.CoolingRate,
+			L: r.params.Steps, E: r.params.StagnationLimit, Seed: r.params.Seed}
+	}
+	if r.anneal != nil {
+		run.Anneal = &jsonAnnealStats{
+			Temperature: r.anneal.Temperature,
+			Energy:      r.anneal.Energy,
+			BestEpoch:   r.anneal.BestEpoch,
+			Iterations:  r.anneal.Iterations,
+			Epochs:      r.anneal.Epochs,
+			Accepted:    r.anneal.Accepted,
+			Rejected:    r.anneal.Rejected,
+			Reheats:     r.anneal.Reheats,
+		}
+	}
+	if r.exact != nil {
+		run.Exact = &jsonExactStats{LowerBound: r.exact.LowerBound, Optimal: r.exact.Optimal, Nodes: r.exact.Nodes}
+	}
+	for i, container := range solution.Containers {
+		bin := jsonBin{Load: container.Load(), Items: make([]jsonItem, container.Len())}
+		for j, item := range container.Items() {
+			bin.Items[j] = jsonItem{ID: item.ID, Weight: item.Weight}
+			if payload, ok := item.Payload.(json.RawMessage); ok {
+				bin.Items[j].Payload = payload
+			}
+		}
+		run.Bins[i] = bin
+	}
+	return run
+}
+
+// Записывает результаты всех запусков для одной задачи в формате JSON
+func writeJSON(writer io.Writer, output jsonSolution) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
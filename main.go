@@ -2,9 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"time"
 
 	"./packing"
 )
@@ -57,66 +64,468 @@ func readData(filename string) ([]int, int, error) {
 	return weights, capacity, nil
 }
 
-func check(err error) {
-	if err != nil {
-		panic(err)
+// Записывает задачу в формате readData
+func writeData(writer io.Writer, weights []int, capacity int) error {
+	buffered := bufio.NewWriter(writer)
+	fmt.Fprintln(buffered, capacity)
+	fmt.Fprintln(buffered, len(weights))
+	for _, weight := range weights {
+		fmt.Fprintln(buffered, weight)
 	}
+	return buffered.Flush()
 }
 
-type parameters struct {
-	T float64
-	r float64
-	L int
-	E int
+// Возвращает веса предметов
+func itemWeights(items []packing.Item) []int {
+	weights := make([]int, len(items))
+	for i, item := range items {
+		weights[i] = item.Weight
+	}
+	return weights
 }
 
-func main() {
-	args := os.Args[1:]
-	if len(args) != 1 {
-		fmt.Println("Необходимо указание входного файла!")
-		return
+// Выводит ход работы алгоритма имитации отжига в одну строку
+func printProgress(progress packing.Progress) {
+	fmt.Printf("\rСчётчик неизмененных решений (P) - %2d | Температура (T) - %g", progress.Stagnation, progress.Temperature)
+}
+
+// Функции энергии, доступные для выбора из командной строки
+var energies = map[string]packing.Energy{
+	"unfilled":     packing.UnfilledEnergy{},
+	"fill-squared": packing.FillSquaredEnergy{},
+	"bins-slack":   packing.BinsSlackEnergy{},
+}
+
+// Операторы окрестности, доступные для выбора из командной строки
+var moves = map[string]packing.Move{
+	"shift":  packing.ShiftMove{},
+	"swap":   packing.SwapMove{},
+	"swap21": packing.Swap21Move{},
+	"swap22": packing.Swap22Move{},
+	"eject":  packing.EjectMove{},
+	"chain":  packing.ChainMove{},
+}
+
+// Разбирает список операторов окрестности с весами
+// вида "shift=1,swap=1,eject=0.2"
+func parseMoves(spec string) ([]packing.WeightedMove, error) {
+	var result []packing.WeightedMove
+	for _, field := range strings.Split(spec, ",") {
+		parts := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		move, ok := moves[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("неизвестный оператор окрестности: %q", parts[0])
+		}
+		weight := 1.0
+		if len(parts) == 2 {
+			var err error
+			weight, err = strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				return nil, fmt.Errorf("неверный вес оператора %q: %v", parts[0], err)
+			}
+		}
+		result = append(result, packing.WeightedMove{Move: move, Weight: weight})
+	}
+	return result, nil
+}
+
+// Возвращает закон охлаждения по названию; параметры законов
+// подбираются так, чтобы первая смена температуры совпадала
+// с геометрическим охлаждением с коэффициентом r от фактической
+// начальной температуры (в том числе подобранной автоматически)
+func newCooling(name string, r float64) (packing.CoolingSchedule, error) {
+	switch name {
+	case "geometric":
+		return packing.GeometricCooling{Rate: r}, nil
+	case "linear":
+		return packing.LinearCooling{Rate: r}, nil
+	case "log":
+		return packing.LogarithmicCooling{}, nil
+	case "lundy-mees":
+		return packing.LundyMeesCooling{Rate: r}, nil
+	case "adaptive":
+		return packing.AdaptiveCooling{Target: 0.5, Rate: r}, nil
+	}
+	return nil, fmt.Errorf("неизвестный закон охлаждения: %q", name)
+}
+
+// Флаги командной строки отжига, общие для всех команд;
+// T, r, L и E каждая команда задаёт по-своему
+type annealFlags struct {
+	energy     *string
+	moves      *string
+	cooling    *string
+	reheating  packing.Reheating
+	acceptance *float64
+}
+
+// Регистрирует флаги отжига в наборе flags
+func addAnnealFlags(flags *flag.FlagSet) *annealFlags {
+	f := &annealFlags{}
+	f.energy = flags.String("energy", "unfilled", "функция энергии: unfilled, fill-squared или bins-slack")
+	f.moves = flags.String("moves", "shift=1,swap=1",
+		"операторы окрестности с весами: shift, swap, swap21, swap22, eject, chain")
+	f.cooling = flags.String("cooling", "geometric", "закон охлаждения: geometric, linear, log, lundy-mees или adaptive")
+	flags.IntVar(&f.reheating.Threshold, "reheat", 0, "число смен температуры без изменения решения до нагрева (0 - без нагрева)")
+	flags.Float64Var(&f.reheating.Ratio, "reheat-ratio", packing.DefaultReheatRatio, "температура после нагрева в долях от начальной")
+	flags.IntVar(&f.reheating.Limit, "reheat-limit", packing.DefaultReheatLimit, "наибольшее число нагревов")
+	f.acceptance = flags.Float64("auto-temperature", 0,
+		"подбор начальной температуры по доле принимаемых ухудшений, например 0.8 (0 - не подбирать)")
+	return f
+}
+
+// Возвращает параметры отжига param, дополненные значениями флагов;
+// закон охлаждения строится по коэффициенту param.CoolingRate
+func (f *annealFlags) options(param packing.AnnealOptions) (packing.AnnealOptions, error) {
+	var ok bool
+	if param.Energy, ok = energies[*f.energy]; !ok {
+		return param, fmt.Errorf("неизвестная функция энергии: %q", *f.energy)
+	}
+	var err error
+	if param.Moves, err = parseMoves(*f.moves); err != nil {
+		return param, err
 	}
+	if param.Cooling, err = newCooling(*f.cooling, param.CoolingRate); err != nil {
+		return param, err
+	}
+	param.Reheating = f.reheating
+	param.Calibration = packing.Calibration{Acceptance: *f.acceptance}
+	return param, nil
+}
+
+// Параметры отжига, которые перебираются, если T, r, L и E
+// не заданы в командной строке
+var defaultParams = []packing.AnnealOptions{
+	packing.AnnealOptions{Temperature: 1000.0, CoolingRate: 0.8, Steps: 100, StagnationLimit: 5},
+	packing.AnnealOptions{Temperature: 2000.0, CoolingRate: 0.83, Steps: 200, StagnationLimit: 10},
+	packing.AnnealOptions{Temperature: 3000.0, CoolingRate: 0.87, Steps: 300, StagnationLimit: 15},
+	packing.AnnealOptions{Temperature: 4000.0, CoolingRate: 0.92, Steps: 400, StagnationLimit: 20},
+	packing.AnnealOptions{Temperature: 5000.0, CoolingRate: 0.99, Steps: 500, StagnationLimit: 25},
+}
+
+// Эвристики, доступные для выбора из командной строки
+var heuristics = map[string]func([]packing.Item, int) (packing.Solution, error){
+	"bestfit":     packing.BestFitItems,
+	"fastbestfit": packing.FastBestFitItems,
+	"nf":          packing.NextFitItems,
+	"ff":          packing.FirstFitItems,
+	"wf":          packing.WorstFitItems,
+	"awf":         packing.AlmostWorstFitItems,
+	"ffd":         packing.FirstFitDecreasingItems,
+	"bfd":         packing.BestFitDecreasingItems,
+}
 
-	weights, capacity, err := readData(args[0])
-	check(err)
+// Возвращает параметры отжига: если хотя бы один из параметров
+// T, r, L, E задан, то единственный набор из заданных значений,
+// иначе - перебираемые по умолчанию наборы
+func annealParams(set map[string]bool, T, r float64, L, E int) []packing.AnnealOptions {
+	if !set["T"] && !set["r"] && !set["L"] && !set["E"] {
+		params := make([]packing.AnnealOptions, len(defaultParams))
+		copy(params, defaultParams)
+		return params
+	}
+	return []packing.AnnealOptions{
+		packing.AnnealOptions{Temperature: T, CoolingRate: r, Steps: L, StagnationLimit: E},
+	}
+}
+
+// Результат одного запуска алгоритма
+type report struct {
+	algorithm string
+	run       int
+	solution  packing.Solution
+	elapsed   time.Duration
+	// параметры и результат отжига (только для anneal)
+	params *packing.AnnealOptions
+	anneal *packing.AnnealResult
+	// результат точного алгоритма (только для exact)
+	exact *packing.ExactResult
+}
 
-	params := []parameters{
-		parameters{T: 1000.0, r: 0.8, L: 100, E: 5},
-		parameters{T: 2000.0, r: 0.83, L: 200, E: 10},
-		parameters{T: 3000.0, r: 0.87, L: 300, E: 15},
-		parameters{T: 4000.0, r: 0.92, L: 400, E: 20},
-		parameters{T: 5000.0, r: 0.99, L: 500, E: 25},
+// Выводит сведения о задаче
+func printInstance(inst instance, bounds packing.Bounds) {
+	fmt.Println("Задача -", inst.name)
+	fmt.Println("Вместимость (W) =", inst.capacity)
+	fmt.Println("Количество предметов (n) =", len(inst.items))
+	sum := 0
+	for _, item := range inst.items {
+		sum += item.Weight
 	}
+	fmt.Println("Сумма предметов - ", sum)
+	fmt.Printf("Нижние оценки (L1, L2, L3) - %d, %d, %d\n", bounds.L1, bounds.L2, bounds.L3)
+	if inst.best > 0 {
+		fmt.Println("Лучшее известное число контейнеров -", inst.best)
+	}
+	fmt.Println()
+}
+
+// Выводит параметры отжига и зерно генератора случайных чисел
+func printParams(param packing.AnnealOptions) {
+	fmt.Println("Зерно генератора случайных чисел =", param.Seed)
+	fmt.Println("Температура (T) =", param.Temperature)
+	fmt.Println("Коэффициент охлаждения (r) =", param.CoolingRate)
+	fmt.Println("Число шагов алгоритма (L) =", param.Steps)
+	fmt.Println("Число смен температуры без изменения текущего решения (E) =", param.StagnationLimit)
+}
 
-	for _, param := range params {
-		fmt.Println("Вместимость (W) =", capacity)
-		fmt.Println("Количество предметов (n) =", len(weights))
-		sum := 0
-		for _, weight := range weights {
-			sum += weight
+// Выводит результат запуска в текстовом виде
+func printReport(r report, items []packing.Item, bounds packing.Bounds) {
+	solution := r.solution
+	capacity := solution.Capacity
+	switch {
+	case r.anneal != nil:
+		result := r.anneal
+		if r.params.Calibration.Acceptance > 0 {
+			fmt.Println("Подобранная начальная температура (T) =", result.Temperature)
 		}
-		fmt.Println("Сумма предметов - ", sum)
-		fmt.Println()
-		fmt.Println("Температура (T) =", param.T)
-		fmt.Println("Коэффициент охлаждения (r) =", param.r)
-		fmt.Println("Число шагов алгоритма (L) =", param.L)
-		fmt.Println("Число смен температуры без изменения текущего решения (E) =", param.E)
-
-		containers := packing.SimulatedAnnealing(weights, capacity, param.T, param.r, param.L, param.E)
-		fmt.Println("Общее количество контейнеров:", len(containers))
-		for i, container := range containers {
-			paddingPercentage := float32(container.GetPadding(capacity)) / float32(capacity) * 100.0
-			fmt.Printf("%5d:\t%6.2f%%\n", i, 100.0-paddingPercentage)
+		fmt.Printf("Рассмотрено решений - %d (принято - %d, отклонено - %d), смен температуры - %d, время - %v\n",
+			result.Iterations, result.Accepted, result.Rejected, result.Epochs, result.Elapsed)
+		fmt.Println("Лучшее решение найдено на смене температуры -", result.BestEpoch)
+	case r.exact != nil:
+		fmt.Printf("Алгоритм - %s, время - %v\n", r.algorithm, r.elapsed)
+		fmt.Printf("Оптимальность доказана - %v, просмотрено узлов - %d\n", r.exact.Optimal, r.exact.Nodes)
+	default:
+		fmt.Printf("Алгоритм - %s, время - %v\n", r.algorithm, r.elapsed)
+	}
+
+	if err := packing.ValidateItems(items, capacity, solution.Containers); err != nil {
+		fmt.Println("Ошибка проверки решения:", err)
+	}
+	fmt.Printf("Общее количество контейнеров: %d (нижняя оценка - %d, отклонение - %d)\n",
+		solution.NumBins(), bounds.Best(), bounds.Gap(solution))
+	for i, container := range solution.Containers {
+		paddingPercentage := float32(container.GetPadding(capacity)) / float32(capacity) * 100.0
+		fmt.Printf("%5d:\t%6.2f%%\t%v\n", i, 100.0-paddingPercentage, container.IDs())
+	}
+
+	fmt.Printf("Процент заполненности контейнеров: %.2f%%\n\n\n", solution.FillRatio()*100.0)
+}
+
+// Заголовок таблицы результатов в формате CSV
+var csvHeader = []string{"instance", "algorithm", "run", "T", "r", "L", "E", "seed", "bins", "lower_bound", "best_known", "gap", "fill", "elapsed_ms", "valid"}
+
+// Возвращает строку таблицы результатов в формате CSV
+func csvRecord(r report, inst instance, bounds packing.Bounds) []string {
+	var T, rate, L, E, seed string
+	if r.params != nil {
+		T = strconv.FormatFloat(r.params.Temperature, 'g', -1, 64)
+		if r.anneal != nil {
+			// фактическая начальная температура, в том числе подобранная
+			T = strconv.FormatFloat(r.anneal.Temperature, 'g', -1, 64)
 		}
+		rate = strconv.FormatFloat(r.params.CoolingRate, 'g', -1, 64)
+		L = strconv.Itoa(r.params.Steps)
+		E = strconv.Itoa(r.params.StagnationLimit)
+		seed = strconv.FormatInt(r.params.Seed, 10)
+	}
+	var best string
+	if inst.best > 0 {
+		best = strconv.Itoa(inst.best)
+	}
+	valid := packing.ValidateItems(inst.items, r.solution.Capacity, r.solution.Containers) == nil
+	return []string{
+		inst.name,
+		r.algorithm,
+		strconv.Itoa(r.run),
+		T, rate, L, E, seed,
+		strconv.Itoa(r.solution.NumBins()),
+		strconv.Itoa(bounds.Best()),
+		best,
+		strconv.Itoa(bounds.Gap(r.solution)),
+		strconv.FormatFloat(r.solution.FillRatio(), 'f', 4, 64),
+		strconv.FormatFloat(float64(r.elapsed)/float64(time.Millisecond), 'f', 3, 64),
+		strconv.FormatBool(valid),
+	}
+}
+
+// Выполняет один запуск алгоритма; param используется только
+// отжигом, timeLimit - отжигом (через param) и точным алгоритмом.
+// При прерывании отжига возвращается лучшее найденное решение
+// вместе с ошибкой контекста
+func runAlgorithm(ctx context.Context, algorithm string, inst instance, param packing.AnnealOptions,
+	timeLimit time.Duration) (report, error) {
+	switch algorithm {
+	case "anneal":
+		result, err := packing.SimulatedAnnealingItemsContext(ctx, inst.items, inst.capacity, param)
+		return report{algorithm: algorithm, solution: result.Solution, elapsed: result.Elapsed,
+			params: &param, anneal: &result}, err
+	case "exact":
+		start := time.Now()
+		result, err := packing.ExactItems(inst.items, inst.capacity, packing.ExactOptions{TimeLimit: timeLimit})
+		return report{algorithm: algorithm, solution: result.Solution, elapsed: time.Since(start), exact: &result}, err
+	}
+	heuristic, ok := heuristics[algorithm]
+	if !ok {
+		return report{}, fmt.Errorf("неизвестный алгоритм: %q", algorithm)
+	}
+	start := time.Now()
+	solution, err := heuristic(inst.items, inst.capacity)
+	return report{algorithm: algorithm, solution: solution, elapsed: time.Since(start)}, err
+}
+
+// Подкоманды: первый аргумент командной строки выбирает подкоманду,
+// без неё выполняется упаковка одного файла
+var commands = map[string]func([]string) error{
+	"bench":    benchCommand,
+	"generate": generateCommand,
+	"tune":     tuneCommand,
+}
 
-		// вычисление процента заполненности
-		var fillPercentage float64
-		for _, container := range containers {
-			padding := container.GetPadding(capacity)
-			fillPercentage += float64(capacity-padding) / float64(capacity) * 100.0
+// Команда по умолчанию: упаковка задач одного файла
+func packCommand(args []string) error {
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	algorithm := flags.String("algo", "anneal",
+		"алгоритм: anneal, exact, bestfit, fastbestfit, nf, ff, wf, awf, ffd или bfd")
+	temperature := flags.Float64("T", 1000, "начальная температура")
+	coolingRate := flags.Float64("r", 0.8, "коэффициент охлаждения")
+	steps := flags.Int("L", 100, "число шагов алгоритма при одной температуре")
+	stagnation := flags.Int("E", 5, "число смен температуры без изменения решения до остановки")
+	seed := flags.Int64("seed", 0, "зерно генератора случайных чисел первого запуска, следующие получают seed+1, seed+2, ... (по умолчанию - текущее время)")
+	timeLimit := flags.Duration("time", 0, "ограничение времени одного запуска, например 10s (0 - без ограничения)")
+	runs := flags.Int("runs", 1, "число повторений каждого запуска")
+	format := flags.String("format", "text", "формат вывода: text, csv или json")
+	inputFormat := flags.String("input", "auto", "формат входного файла: "+strings.Join(inputFormats, ", "))
+	anneal := addAnnealFlags(flags)
+	flags.Parse(args)
+
+	// флаги, заданные явно
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("необходимо указание входного файла")
+	}
+
+	_, isHeuristic := heuristics[*algorithm]
+	if *algorithm != "anneal" && *algorithm != "exact" && !isHeuristic {
+		return fmt.Errorf("неизвестный алгоритм: %q", *algorithm)
+	}
+	if *format != "text" && *format != "csv" && *format != "json" {
+		return fmt.Errorf("неизвестный формат вывода: %q", *format)
+	}
+	knownInput := false
+	for _, name := range inputFormats {
+		knownInput = knownInput || name == *inputFormat
+	}
+	if !knownInput {
+		return fmt.Errorf("неизвестный формат входного файла: %q", *inputFormat)
+	}
+	if _, err := anneal.options(packing.AnnealOptions{}); err != nil {
+		return err
+	}
+
+	instances, err := readInstances(flags.Arg(0), *inputFormat)
+	if err != nil {
+		return err
+	}
+	if err := checkInstances(instances); err != nil {
+		return fmt.Errorf("некорректные входные данные: %v", err)
+	}
+
+	if !set["seed"] {
+		*seed = time.Now().UnixNano()
+	}
+	// параметры отжига; для остальных алгоритмов - один пустой набор
+	params := []packing.AnnealOptions{packing.AnnealOptions{}}
+	if *algorithm == "anneal" {
+		params = annealParams(set, *temperature, *coolingRate, *steps, *stagnation)
+		for i := range params {
+			if params[i], err = anneal.options(params[i]); err != nil {
+				return err
+			}
+			params[i].TimeLimit = *timeLimit
 		}
-		fillPercentage /= float64(len(containers))
+	}
+
+	// по Ctrl+C отжиг останавливается и выводится текущее решение
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var table *csv.Writer
+	if *format == "csv" {
+		table = csv.NewWriter(os.Stdout)
+		defer table.Flush()
+		if err := table.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	// в формате JSON для каждой задачи выводится отдельный
+	// документ, в том числе при прерывании
+	var document *jsonSolution
+	flush := func() error {
+		if document == nil {
+			return nil
+		}
+		err := writeJSON(os.Stdout, *document)
+		document = nil
+		return err
+	}
+	defer flush()
 
-		fmt.Printf("Процент заполненности контейнеров: %.2f%%\n\n\n", fillPercentage)
+	text := *format == "text"
+	for _, inst := range instances {
+		weights := itemWeights(inst.items)
+		bounds := packing.LowerBound(weights, inst.capacity)
+		if *format == "json" {
+			if err := flush(); err != nil {
+				return err
+			}
+			document = &jsonSolution{Name: inst.name, BestKnown: inst.best, Capacity: inst.capacity,
+				Items: len(inst.items), LowerBound: bounds.Best(), Runs: []jsonRun{}}
+		}
+
+		for p, param := range params {
+			for run := 1; run <= *runs; run++ {
+				// каждый запуск получает своё зерно, поэтому любой из них
+				// воспроизводится отдельно с -seed, равным этому зерну
+				param.Seed = *seed + int64(p*(*runs)+run-1)
+				if text {
+					printInstance(inst, bounds)
+					if *algorithm == "anneal" {
+						printParams(param)
+						param.Observer = packing.ObserverFunc(printProgress)
+					}
+				}
+				r, err := runAlgorithm(ctx, *algorithm, inst, param, *timeLimit)
+				if text && *algorithm == "anneal" {
+					fmt.Println()
+				}
+				r.run = run
+
+				switch {
+				case table != nil:
+					if err := table.Write(csvRecord(r, inst, bounds)); err != nil {
+						return err
+					}
+				case document != nil:
+					document.Runs = append(document.Runs, newJSONRun(r, inst.items, bounds))
+				default:
+					printReport(r, inst.items, bounds)
+				}
+				if err != nil && ctx.Err() != nil {
+					return fmt.Errorf("работа прервана: %v", err)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return flush()
+}
+
+func main() {
+	command, args := packCommand, os.Args[1:]
+	if len(os.Args) > 1 {
+		if subcommand, ok := commands[os.Args[1]]; ok {
+			command, args = subcommand, os.Args[2:]
+		}
+	}
+	if err := command(args); err != nil {
+		fmt.Fprintln(os.Stderr, "Ошибка:", err)
+		os.Exit(1)
 	}
 }
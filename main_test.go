@@ -1,9 +1,302 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
+
+	"./packing"
 )
 
 func TestReadData(t *testing.T) {
 
 }
+
+func TestParseMoves(t *testing.T) {
+	result, err := parseMoves("shift=2, eject=0.5,chain")
+	if err != nil || len(result) != 3 {
+		t.Fatal("result:", result, "| error:", err)
+	}
+	if result[0].Weight != 2 || result[1].Weight != 0.5 || result[2].Weight != 1 {
+		t.Error("result:", result)
+	}
+
+	for _, spec := range []string{ "unknown=1", "swap=x", "" } {
+		if _, err := parseMoves(spec); err == nil {
+			t.Error("spec:", spec, "| expected error")
+		}
+	}
+}
+
+func TestNewCooling(t *testing.T) {
+	// первая смена температуры совпадает с геометрическим охлаждением
+	// при любой фактической начальной температуре, в том числе подобранной
+	for _, name := range []string{ "geometric", "linear", "lundy-mees", "adaptive" } {
+		cooling, err := newCooling(name, 0.8)
+		if err != nil {
+			t.Fatal(name, "error:", err)
+		}
+		for _, T := range []float64{ 1000, 4.48 } {
+			state := packing.CoolingState{ Initial: T, Temperature: T, Epoch: 1, AcceptanceRate: 0.5 }
+			if result := cooling.Next(state); math.Abs(result-0.8*T) > 1e-9 {
+				t.Error(name, "result:", result, "| expected:", 0.8*T)
+			}
+		}
+	}
+	if _, err := newCooling("unknown", 0.8); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAnnealFlags(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	anneal := addAnnealFlags(flags)
+	if err := flags.Parse([]string{ "-cooling", "linear", "-reheat", "2", "-auto-temperature", "0.8", "-moves", "eject" }); err != nil {
+		t.Fatal(err)
+	}
+	// закон охлаждения строится по коэффициенту каждого набора
+	param, err := anneal.options(packing.AnnealOptions{ Temperature: 10, CoolingRate: 0.5 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if param.Cooling != (packing.LinearCooling{ Rate: 0.5 }) || param.Reheating.Threshold != 2 ||
+		param.Reheating.Ratio != packing.DefaultReheatRatio || param.Calibration.Acceptance != 0.8 ||
+		len(param.Moves) != 1 || param.Energy != (packing.UnfilledEnergy{}) || param.Temperature != 10 {
+		t.Error("result:", param)
+	}
+
+	if err := flags.Parse([]string{ "-energy", "unknown" }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := anneal.options(packing.AnnealOptions{}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestAnnealParams(t *testing.T) {
+	// без заданных параметров перебираются наборы по умолчанию
+	params := annealParams(map[string]bool{ "seed": true }, 1, 0.5, 10, 2)
+	if len(params) != len(defaultParams) || params[0].Temperature != defaultParams[0].Temperature {
+		t.Error("result:", params)
+	}
+
+	// незаданные параметры берутся из значений по умолчанию флагов
+	params = annealParams(map[string]bool{ "T": true }, 1, 0.8, 100, 5)
+	if len(params) != 1 || params[0].Temperature != 1 || params[0].CoolingRate != 0.8 ||
+		params[0].Steps != 100 || params[0].StagnationLimit != 5 {
+		t.Error("result:", params)
+	}
+}
+
+func TestCSVRecord(t *testing.T) {
+	weights := []int{ 4, 6, 5 }
+	solution, err := packing.BestFit(weights, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	param := packing.AnnealOptions{ Temperature: 10, CoolingRate: 0.5, Steps: 20, StagnationLimit: 3, Seed: 42 }
+	// в столбце T - подобранная начальная температура
+	result := packing.AnnealResult{ Solution: solution, Temperature: 4.5 }
+	r := report{ algorithm: "anneal", run: 2, solution: solution, elapsed: 1500 * time.Microsecond, params: &param, anneal: &result }
+	inst := instance{ name: "demo", capacity: 10, items: packing.NewItems(weights), best: 2 }
+	record := csvRecord(r, inst, packing.LowerBound(weights, 10))
+	expected := []string{ "demo", "anneal", "2", "4.5", "0.5", "20", "3", "42", "2", "2", "2", "0", "0.7500", "1.500", "true" }
+	if len(record) != len(csvHeader) || strings.Join(record, ",") != strings.Join(expected, ",") {
+		t.Error("result:", record, "| expected:", expected)
+	}
+}
+
+func TestDecodeInstance(t *testing.T) {
+	input := `{"name": "demo", "capacity": 10, "metadata": {"source": "test"},
+		"items": [{"id": 7, "weight": 4, "payload": {"sku": "a"}}, {"id": 3, "weight": 6}]}`
+	result, err := decodeInstance(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := result.items
+	if result.name != "demo" || result.capacity != 10 || len(items) != 2 || items[0].ID != 7 || items[0].Weight != 4 || items[1].Payload != nil {
+		t.Error("result:", result)
+	}
+	if payload, ok := items[0].Payload.(json.RawMessage); !ok || string(payload) != `{"sku": "a"}` {
+		t.Error("payload:", items[0].Payload)
+	}
+
+	samples := []string{
+		`{"capacity": 10, "items": [{"id": 1, "weight": 4}, {"id": 1, "weight": 6}]}`,
+		`{"capacity": 10, "items": [{"id": 1, "size": 4}]}`,
+		`{"capacity": 10, "items": [`,
+	}
+	for _, sample := range samples {
+		if _, err := decodeInstance(strings.NewReader(sample)); err == nil {
+			t.Error("input:", sample, "| expected error")
+		}
+	}
+}
+
+func TestCheckInstances(t *testing.T) {
+	// ошибка называет предмет по идентификатору из JSON, а не по индексу
+	oversized, err := decodeInstance(strings.NewReader(`{"name": "demo", "capacity": 30, "items": [{"id": 5, "weight": 4}, {"id": 71, "weight": 40}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = checkInstances([]instance{ oversized })
+	if err == nil || !strings.Contains(err.Error(), "demo: предмет 71") {
+		t.Error("error:", err)
+	}
+}
+
+func TestJSONRun(t *testing.T) {
+	items := []packing.Item{
+		packing.Item{ ID: 7, Weight: 4, Payload: json.RawMessage(`"a"`) },
+		packing.Item{ ID: 3, Weight: 6 },
+		packing.Item{ ID: 9, Weight: 5 },
+	}
+	solution, err := packing.FirstFitDecreasingItems(items, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := itemWeights(items)
+	r := report{ algorithm: "ffd", run: 1, solution: solution, elapsed: time.Millisecond }
+	run := newJSONRun(r, items, packing.LowerBound(weights, 10))
+	if run.NumBins != 2 || run.Gap != 0 || !run.Valid || run.Params != nil || run.Anneal != nil {
+		t.Error("result:", run)
+	}
+	// зерно генератора выводится вместе с параметрами отжига
+	param := packing.AnnealOptions{ Temperature: 10, CoolingRate: 0.5, Steps: 20, StagnationLimit: 3, Seed: 42 }
+	annealed := newJSONRun(report{ algorithm: "anneal", solution: solution, params: &param }, items, packing.LowerBound(weights, 10))
+	if annealed.Params == nil || annealed.Params.Seed != 42 {
+		t.Error("params:", annealed.Params)
+	}
+
+	// решение проверяется по идентификаторам: предметы с теми же
+	// весами, но другими идентификаторами не являются решением задачи
+	other, err := packing.FirstFitDecreasing(weights, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newJSONRun(report{ algorithm: "ffd", solution: other }, items, packing.LowerBound(weights, 10)).Valid {
+		t.Error("solution with foreign ids is valid:", other.Containers)
+	}
+
+	// решение восстанавливается по выводу: идентификаторы,
+	// веса и сопутствующие данные предметов сохраняются
+	var buffer bytes.Buffer
+	if err := writeJSON(&buffer, jsonSolution{ Capacity: 10, Items: 3, Runs: []jsonRun{ run } }); err != nil {
+		t.Fatal(err)
+	}
+	var output jsonSolution
+	if err := json.Unmarshal(buffer.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+	bins := output.Runs[0].Bins
+	expected := []jsonBin{
+		jsonBin{ Load: 10, Items: []jsonItem{ jsonItem{ ID: 3, Weight: 6 }, jsonItem{ ID: 7, Weight: 4, Payload: json.RawMessage(`"a"`) } } },
+		jsonBin{ Load: 5, Items: []jsonItem{ jsonItem{ ID: 9, Weight: 5 } } },
+	}
+	if len(bins) != len(expected) {
+		t.Fatal("bins:", bins, "| expected:", expected)
+	}
+	for i := range expected {
+		if bins[i].Load != expected[i].Load || len(bins[i].Items) != len(expected[i].Items) {
+			t.Fatal("bins:", bins, "| expected:", expected)
+		}
+		for j, item := range expected[i].Items {
+			result := bins[i].Items[j]
+			if result.ID != item.ID || result.Weight != item.Weight || string(result.Payload) != string(item.Payload) {
+				t.Error("item:", result, "| expected:", item)
+			}
+		}
+	}
+}
+
+func TestParseORLibrary(t *testing.T) {
+	input := ` 2
+ u4_00
+ 150 4 2
+ 42
+ 69
+ 67
+ 57
+ t3_00
+ 100.0 3 1
+ 33.5
+ 33.25
+ 33.25
+`
+	instances, err := parseORLibrary(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := []struct {
+		name string
+		capacity int
+		weights []int
+		best int
+	}{
+		{
+			"u4_00",
+			150,
+			[]int{ 42, 69, 67, 57 },
+			2,
+		}, {
+			// дробные веса умножаются на 100 вместе с вместимостью
+			"t3_00",
+			10000,
+			[]int{ 3350, 3325, 3325 },
+			1,
+		},
+	}
+	if len(instances) != len(samples) {
+		t.Fatal("result:", instances)
+	}
+	for i, sample := range samples {
+		result := instances[i]
+		if result.name != sample.name || result.capacity != sample.capacity || result.best != sample.best ||
+			fmt.Sprint(itemWeights(result.items)) != fmt.Sprint(sample.weights) {
+			t.Error("result:", result, "| expected:", sample)
+		}
+	}
+
+	for _, sample := range []string{ "", "1\nu\n150 3 2\n1 2", "1\nu\n150 1 2\n-5", "x" } {
+		if _, err := parseORLibrary(strings.NewReader(sample)); err == nil {
+			t.Error("input:", sample, "| expected error")
+		}
+	}
+}
+
+func TestParseBPPLIB(t *testing.T) {
+	samples := []struct {
+		input string
+		weights []int
+	}{
+		{
+			"3\n100\n50\n30\n20\n",
+			[]int{ 50, 30, 20 },
+		}, {
+			// вес и кратность каждого типа предметов
+			"2\n100\n50 2\n30 3\n",
+			[]int{ 50, 50, 30, 30, 30 },
+		},
+	}
+	for _, sample := range samples {
+		result, err := parseBPPLIB(strings.NewReader(sample.input), "demo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.name != "demo" || result.capacity != 100 ||
+			fmt.Sprint(itemWeights(result.items)) != fmt.Sprint(sample.weights) {
+			t.Error("result:", result, "| expected:", sample.weights)
+		}
+	}
+
+	for _, sample := range []string{ "3\n100\n50 30", "2\n100\nx 1", "5" } {
+		if _, err := parseBPPLIB(strings.NewReader(sample), "demo"); err == nil {
+			t.Error("input:", sample, "| expected error")
+		}
+	}
+}
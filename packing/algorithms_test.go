@@ -1,6 +1,7 @@
 package packing
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -13,31 +14,31 @@ func TestBestFit(t *testing.T) {
 		{
 			nil,
 			0,
-			[]Container{
-				Container{ weights: nil },
-			},
+			nil,
 		},
 		{
 			[]int{},
 			0,
-			[]Container{
-				Container{ weights: []int{} },
-			},
+			[]Container{},
 		},
 		{
 			[]int{ 1, 2, 3, 4 },
 			5,
 			[]Container{
-				Container{ weights: []int{ 1, 2 } },
-				Container{ weights: []int{ 3 } },
-				Container{ weights: []int{ 4 } },
+				fromWeights(1, 2),
+				fromWeights(3),
+				fromWeights(4),
 			},
 		},
 	}
 
 	for _, sample := range samples {
 		expected := sample.containers
-		result := BestFit(sample.weights, sample.capacity)
+		solution, err := BestFit(sample.weights, sample.capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := solution.Containers
 		if !areEqual(result, expected) {
 			t.Error("result: ", result, "| expected: ", expected)
 		}
@@ -63,7 +64,7 @@ func TestNewSolution(t *testing.T) {
 
 	for _, sample := range samples {
 		expected := sample.newContainers
-		result := NewSolution(sample.containers, sample.capacity)
+		result := NewSolution(sample.containers, sample.capacity, rand.New(rand.NewSource(1)))
 		if !areEqual(result, expected) {
 			t.Error("result:", result, "| expected:", expected)
 		}
@@ -77,15 +78,15 @@ func TestRandNewSolution(t *testing.T) {
 	} {
 		{
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3, 7 } },
-				Container{ weights: []int{ 6, 8 } },
-				Container{ weights: []int{ 1, 1, 10 } },
+				fromWeights(1, 2, 3, 7),
+				fromWeights(6, 8),
+				fromWeights(1, 1, 10),
 			},
 			15,
 		}, {
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3 } },
-				Container{ weights: []int{ 6 } },
+				fromWeights(1, 2, 3),
+				fromWeights(6),
 			},
 			10,
 		},
@@ -94,11 +95,30 @@ func TestRandNewSolution(t *testing.T) {
 	for _, sample := range samples {
 		initial := sample.containers
 		capacity := sample.capacity
-		solution := NewSolution(sample.containers, sample.capacity)
+		solution := NewSolution(sample.containers, sample.capacity, rand.New(rand.NewSource(1)))
 		t.Log("containers:", initial, "| capacity:", capacity, "| new solution:", solution)
 	}
 }
 
+func TestNewSolutionSeed(t *testing.T) {
+	// чётные веса и нечётная вместимость: полностью заполненных
+	// контейнеров не бывает, поэтому перемещение всегда возможно
+	weights := []int{ 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40 }
+	capacity := 101
+
+	first := bestFit(weights, capacity)
+	second := bestFit(weights, capacity)
+	firstRng := rand.New(rand.NewSource(7))
+	secondRng := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		first = NewSolution(first, capacity, firstRng)
+		second = NewSolution(second, capacity, secondRng)
+		if !areEqual(first, second) {
+			t.Fatal("step:", i, "| first:", first, "| second:", second)
+		}
+	}
+}
+
 func TestCalculatePadding(t *testing.T) {
 	samples := []struct {
 		containers []Container
@@ -107,23 +127,23 @@ func TestCalculatePadding(t *testing.T) {
 	} {
 		{
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3, 7 } },
-				Container{ weights: []int{ 6, 8 } },
-				Container{ weights: []int{ 1, 1, 10 } },
+				fromWeights(1, 2, 3, 7),
+				fromWeights(6, 8),
+				fromWeights(1, 1, 10),
 			},
 			15,
 			6,
 		}, {
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3 } },
-				Container{ weights: []int{ 6 } },
+				fromWeights(1, 2, 3),
+				fromWeights(6),
 			},
 			10,
 			8,
 		}, {
 			[]Container{
-				Container{ weights: []int{} },
-				Container{ weights: []int{} },
+				Container{ items: []Item{} },
+				Container{ items: []Item{} },
 			},
 			10,
 			20,
@@ -141,4 +161,13 @@ func TestCalculatePadding(t *testing.T) {
 			t.Error("result:", result, "| expected:", expected)
 		}
 	}
-}
\ No newline at end of file
+}
+// bestFit - Возвращает контейнеры решения BestFit
+// для заведомо корректных входных данных
+func bestFit(weights []int, capacity int) []Container {
+	solution, err := BestFit(weights, capacity)
+	if err != nil {
+		panic(err)
+	}
+	return solution.Containers
+}
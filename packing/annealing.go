@@ -0,0 +1,401 @@
+package packing
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// AnnealOptions - Параметры алгоритма имитации отжига
+type AnnealOptions struct {
+	// Temperature - начальная температура (T)
+	Temperature float64
+	// Calibration - автоматический подбор начальной температуры;
+	// если включён, то Temperature используется, только когда
+	// подобрать температуру не удалось
+	Calibration Calibration
+	// CoolingRate - коэффициент охлаждения (r) для геометрического
+	// охлаждения, которое используется, если не задан Cooling
+	CoolingRate float64
+	// Steps - число шагов алгоритма при одной температуре (L)
+	Steps int
+	// StagnationLimit - число смен температуры без изменения
+	// текущего решения, после которого алгоритм завершается (E)
+	StagnationLimit int
+
+	// Seed - зерно генератора случайных чисел: одинаковое
+	// зерно даёт одинаковое решение
+	Seed int64
+	// Rand - источник случайных чисел; если задан, то Seed не используется
+	Rand *rand.Rand
+
+	// InitialSolution - начальное решение; если не задано,
+	// то используется решение алгоритма BestFit
+	InitialSolution []Container
+	// TimeLimit - ограничение времени работы (0 - без ограничения)
+	TimeLimit time.Duration
+
+	// Cooling - закон изменения температуры (может отсутствовать)
+	Cooling CoolingSchedule
+	// Reheating - правило повторного нагрева (по умолчанию отключено)
+	Reheating Reheating
+
+	// Moves - операторы окрестности и их веса; если не заданы,
+	// то используются перемещение и обмен предметов (DefaultMoves)
+	Moves []WeightedMove
+
+	// Energy - функция энергии; если не задана, то
+	// используется количество незаполненных контейнеров
+	Energy Energy
+
+	// Observer - получает сведения о ходе работы после
+	// каждой смены температуры (может отсутствовать)
+	Observer Observer
+}
+
+// Progress - Состояние алгоритма после очередной смены температуры
+type Progress struct {
+	// Epoch - номер смены температуры (начиная с 1)
+	Epoch int
+	// Temperature - новая температура
+	Temperature float64
+	// AcceptanceRate - доля принятых решений при прежней температуре
+	AcceptanceRate float64
+	// Stagnation - текущее число смен температуры
+	// без изменения решения (P)
+	Stagnation int
+	// Reheats - число произведённых повторных нагревов
+	Reheats int
+	// Energy - энергия текущего решения
+	Energy float64
+	// Bins - число контейнеров текущего решения
+	Bins int
+	// BestEnergy - энергия лучшего найденного решения
+	BestEnergy float64
+	// BestBins - число контейнеров лучшего найденного решения
+	BestBins int
+}
+
+// Observer - Наблюдатель за ходом работы алгоритма имитации отжига
+type Observer interface {
+	Observe(progress Progress)
+}
+
+// ObserverFunc - Позволяет использовать обычную функцию как Observer
+type ObserverFunc func(progress Progress)
+
+// Observe - Вызывает саму функцию
+func (f ObserverFunc) Observe(progress Progress) {
+	f(progress)
+}
+
+// DefaultAnnealOptions - Возвращает параметры отжига по умолчанию
+func DefaultAnnealOptions() AnnealOptions {
+	return AnnealOptions{
+		Temperature:     1000.0,
+		CoolingRate:     0.8,
+		Steps:           100,
+		StagnationLimit: 5,
+	}
+}
+
+// AnnealResult - Результат работы алгоритма имитации отжига
+type AnnealResult struct {
+	// Solution - лучшее найденное решение: с наименьшим числом
+	// контейнеров, а среди таких - с наименьшей энергией
+	Solution
+	// Energy - значение функции энергии лучшего решения
+	Energy float64
+	// BestEpoch - номер смены температуры, на которой было найдено
+	// лучшее решение (0 - лучшим осталось начальное решение)
+	BestEpoch int
+
+	// Iterations - общее число рассмотренных решений
+	Iterations int
+	// Epochs - число смен температуры
+	Epochs int
+	// Accepted - число принятых решений
+	Accepted int
+	// Rejected - число отклонённых решений
+	Rejected int
+	// Reheats - число повторных нагревов
+	Reheats int
+	// Temperature - начальная температура (заданная или подобранная)
+	Temperature float64
+	// Elapsed - время работы
+	Elapsed time.Duration
+}
+
+// bestLog - Лучшее найденное решение в виде снимка и журнала
+// изменений: лучшее решение получается применением к снимку первых
+// best изменений журнала. Контейнеры решения во время отжига не
+// изменяются на месте, а заменяются изменениями, поэтому снимку
+// достаточно копии среза. Журнал ограничен размером снимка, так что
+// на одно принятое изменение в среднем приходится O(1) работы
+// помимо затронутых контейнеров
+type bestLog struct {
+	snapshot []Container
+	log      []Change
+	best     int
+	// tracking - можно ли получить текущее решение из снимка и
+	// журнала; если журнал слишком вырос без улучшений, то он
+	// отбрасывается, а снимок остаётся лучшим решением
+	tracking bool
+}
+
+// newBestLog - Создаёт журнал, в котором лучшим является решение containers
+func newBestLog(containers []Container) *bestLog {
+	return &bestLog{snapshot: append([]Container(nil), containers...), tracking: true}
+}
+
+// record - Запоминает изменение, принятое в текущем решении
+func (b *bestLog) record(change Change) {
+	if !b.tracking {
+		return
+	}
+	b.log = append(b.log, change)
+	if len(b.log) <= len(b.snapshot)+moveAttempts {
+		return
+	}
+	// снимок продвигается до лучшего решения, а если журнал
+	// всё ещё слишком длинный, то он отбрасывается
+	for _, change := range b.log[:b.best] {
+		b.snapshot = change.apply(b.snapshot)
+	}
+	b.log = append([]Change(nil), b.log[b.best:]...)
+	b.best = 0
+	if len(b.log) > len(b.snapshot)+moveAttempts {
+		b.log = nil
+		b.tracking = false
+	}
+}
+
+// mark - Отмечает текущее решение containers как лучшее
+func (b *bestLog) mark(containers []Container) {
+	if !b.tracking {
+		b.snapshot = append([]Container(nil), containers...)
+		b.log = nil
+		b.tracking = true
+	}
+	b.best = len(b.log)
+}
+
+// solution - Возвращает лучшее решение
+func (b *bestLog) solution() []Container {
+	for _, change := range b.log[:b.best] {
+		b.snapshot = change.apply(b.snapshot)
+	}
+	b.log = b.log[b.best:]
+	b.best = 0
+	return b.snapshot
+}
+
+/*
+	Алгоритм имитации отжига
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+		options - параметры алгоритма
+	выходные данные:
+		полученное решение (заполенные контейнеры) и
+		статистика работы алгоритма; ошибка *InstanceError, если
+		входные данные некорректны, или *ValidationError, если
+		недопустимо начальное решение
+*/
+func SimulatedAnnealing(weights []int, capacity int, options AnnealOptions) (AnnealResult, error) {
+	return SimulatedAnnealingContext(context.Background(), weights, capacity, options)
+}
+
+/*
+	Алгоритм имитации отжига с возможностью отмены
+	входные данные:
+		ctx - контекст: при его отмене или истечении срока
+			алгоритм останавливается
+		weights - веса предметов
+		capacity - вместимость контейнеров
+		options - параметры алгоритма
+	выходные данные:
+		полученное решение (заполенные контейнеры) и
+		статистика работы алгоритма; при остановке по контексту -
+		лучшее найденное к этому моменту решение и ошибка контекста;
+		при некорректных входных данных - ошибка, как у SimulatedAnnealing
+*/
+func SimulatedAnnealingContext(ctx context.Context, weights []int, capacity int, options AnnealOptions) (AnnealResult, error) {
+	return SimulatedAnnealingItemsContext(ctx, NewItems(weights), capacity, options)
+}
+
+// SimulatedAnnealingItems - Алгоритм имитации отжига
+// для предметов с идентификаторами
+func SimulatedAnnealingItems(items []Item, capacity int, options AnnealOptions) (AnnealResult, error) {
+	return SimulatedAnnealingItemsContext(context.Background(), items, capacity, options)
+}
+
+// SimulatedAnnealingItemsContext - Алгоритм имитации отжига
+// с возможностью отмены для предметов с идентификаторами
+func SimulatedAnnealingItemsContext(ctx context.Context, items []Item, capacity int, options AnnealOptions) (AnnealResult, error) {
+	start := time.Now()
+	var result AnnealResult
+
+	if err := CheckItems(items, capacity); err != nil {
+		return result, err
+	}
+	if options.InitialSolution != nil {
+		if err := ValidateItems(items, capacity, options.InitialSolution); err != nil {
+			return result, err
+		}
+	}
+
+	rng := options.Rand
+	if rng == nil {
+		rng = rand.New(rand.NewSource(options.Seed))
+	}
+
+	var solution []Container
+	if options.InitialSolution != nil {
+		solution = createCopy(options.InitialSolution)
+	} else {
+		packed, _ := BestFitItems(items, capacity)
+		solution = packed.Containers
+	}
+	moves := options.Moves
+	if moves == nil {
+		moves = defaultMoves
+	}
+
+	cooling := options.Cooling
+	if cooling == nil {
+		cooling = GeometricCooling{Rate: options.CoolingRate}
+	}
+
+	objective := options.Energy
+	if objective == nil {
+		objective = UnfilledEnergy{}
+	}
+	energies := newEvaluator(objective, solution, capacity)
+
+	initial := options.Temperature
+	if options.Calibration.Acceptance > 0 {
+		if T, ok := calibrateTemperature(solution, capacity, objective, moves, options.Calibration, rng); ok {
+			initial = T
+		}
+	}
+	result.Temperature = initial
+
+	// лучшее найденное решение хранится как снимок и журнал
+	// принятых изменений, поэтому не копируется при каждом улучшении
+	best := newBestLog(solution)
+	bestBins := len(solution)
+	bestEnergy := energies.energy
+
+	T := initial
+	// текущее число смен температуры
+	// без изменения текущего решения
+	var p int
+	// исчерпано ли отведённое время
+	var timeout bool
+	// ошибка контекста, если алгоритм был остановлен извне
+	var err error
+	for p < options.StagnationLimit {
+		// запоминаем текущее решение для дальнейшего сравнения;
+		// контейнеры решения не изменяются на месте, а заменяются,
+		// поэтому достаточно скопировать срез
+		initialSolution := append([]Container(nil), solution...)
+		// число принятых решений при текущей температуре
+		accepted := result.Accepted
+
+		for i := 0; i < options.Steps; i++ {
+			if options.TimeLimit > 0 && time.Since(start) >= options.TimeLimit {
+				timeout = true
+				break
+			}
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			result.Iterations++
+
+			// изменение предлагается без копирования решения
+			// и применяется к нему только в случае принятия
+			change, ok := propose(moves, solution, capacity, rng)
+			if !ok {
+				result.Rejected++
+				continue
+			}
+			anotherEnergy, state := energies.propose(solution, change)
+			delta := anotherEnergy - energies.energy
+			u := floatUniform(rng, 0, 1)
+			border := -1.0
+			if delta > 0 {
+				border = math.Exp(-delta / T)
+			}
+
+			if delta <= 0 || u <= border {
+				solution = change.apply(solution)
+				energies.accept(anotherEnergy, state)
+				best.record(change)
+				result.Accepted++
+
+				// решение лучше, если в нём меньше контейнеров,
+				// а при равном их числе - если меньше энергия
+				if len(solution) < bestBins || (len(solution) == bestBins && anotherEnergy < bestEnergy) {
+					best.mark(solution)
+					bestBins = len(solution)
+					bestEnergy = anotherEnergy
+					// текущая смена температуры ещё не завершена
+					result.BestEpoch = result.Epochs + 1
+				}
+			} else {
+				result.Rejected++
+			}
+		}
+		// незавершённая смена температуры не учитывается
+		if timeout || err != nil {
+			break
+		}
+		result.Epochs++
+		energies.reset(solution)
+
+		var acceptanceRate float64
+		if options.Steps > 0 {
+			acceptanceRate = float64(result.Accepted-accepted) / float64(options.Steps)
+		}
+		T = cooling.Next(CoolingState{
+			Initial:        initial,
+			Temperature:    T,
+			Epoch:          result.Epochs,
+			AcceptanceRate: acceptanceRate,
+		})
+
+		// если решение не изменилось, то
+		// увеличиваем счётчик
+		if areEqual(solution, initialSolution) {
+			p = p + 1
+		}
+
+		// при застое поднимаем температуру, чтобы выбраться с плато
+		if options.Reheating.due(p, result.Reheats) {
+			T = options.Reheating.temperature(initial)
+			p = 0
+			result.Reheats++
+		}
+
+		if options.Observer != nil {
+			options.Observer.Observe(Progress{
+				Epoch:          result.Epochs,
+				Temperature:    T,
+				AcceptanceRate: acceptanceRate,
+				Stagnation:     p,
+				Reheats:        result.Reheats,
+				Energy:         energies.energy,
+				Bins:           len(solution),
+				BestEnergy:     bestEnergy,
+				BestBins:       bestBins,
+			})
+		}
+	}
+
+	result.Solution = Solution{Containers: best.solution(), Capacity: capacity}
+	result.Energy = bestEnergy
+	result.Elapsed = time.Since(start)
+	return result, err
+}
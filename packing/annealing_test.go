@@ -0,0 +1,190 @@
+package packing
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// exampleWeights - первые 40 предметов примера example1.txt
+var exampleWeights = []int{
+	42, 69, 67, 57, 93, 90, 38, 36, 45, 42, 33, 79, 27, 57, 44, 84, 86, 92, 46, 38,
+	85, 33, 82, 73, 49, 70, 59, 23, 57, 72, 74, 69, 33, 42, 28, 46, 30, 64, 29, 74,
+}
+
+func TestSimulatedAnnealingSeed(t *testing.T) {
+	options := AnnealOptions{Temperature: 1.0, CoolingRate: 0.5, Steps: 50, StagnationLimit: 3, Seed: 7}
+	first, err := SimulatedAnnealing(exampleWeights, 150, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SimulatedAnnealing(exampleWeights, 150, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !areEqual(first.Containers, second.Containers) || first.Iterations != second.Iterations {
+		t.Error("first:", first.Containers, "| second:", second.Containers)
+	}
+}
+
+func TestSimulatedAnnealingTimeLimit(t *testing.T) {
+	// энергия всех решений одинакова, поэтому без
+	// ограничения времени отжиг не остановится
+	weights := []int{ 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40 }
+	options := AnnealOptions{Temperature: 1.0, CoolingRate: 0.9, Steps: 10, StagnationLimit: 3, TimeLimit: 50 * time.Millisecond}
+	result, err := SimulatedAnnealing(weights, 101, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Elapsed > time.Second {
+		t.Error("elapsed:", result.Elapsed, "| limit:", options.TimeLimit)
+	}
+	if result.Accepted+result.Rejected != result.Iterations {
+		t.Error("accepted:", result.Accepted, "| rejected:", result.Rejected, "| iterations:", result.Iterations)
+	}
+	if Validate(weights, 101, result.Containers) != nil {
+		t.Error("invalid packing:", result.Containers)
+	}
+}
+
+func TestSimulatedAnnealingInitialSolution(t *testing.T) {
+	// все контейнеры начального решения заполнены полностью,
+	// поэтому возможны лишь обмены одинаковых предметов
+	initial := renumber(
+		fromWeights(3, 7),
+		fromWeights(7, 3),
+		fromWeights(4, 6),
+		fromWeights(6, 4),
+	)
+	options := AnnealOptions{Temperature: 1.0, CoolingRate: 0.5, Steps: 20, StagnationLimit: 3, InitialSolution: initial}
+	result, err := SimulatedAnnealing([]int{ 3, 7, 7, 3, 4, 6, 6, 4 }, 10, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !areEqual(result.Containers, initial) || result.Epochs != 3 || result.Energy != 0 {
+		t.Error("result:", result.Containers, "| epochs:", result.Epochs, "| energy:", result.Energy)
+	}
+}
+
+func TestSimulatedAnnealingContext(t *testing.T) {
+	weights := []int{ 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40 }
+	options := AnnealOptions{Temperature: 1.0, CoolingRate: 0.9, Steps: 10, StagnationLimit: 3}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err := SimulatedAnnealingContext(ctx, weights, 101, options)
+	if err != context.DeadlineExceeded {
+		t.Error("error:", err, "| expected:", context.DeadlineExceeded)
+	}
+	if Validate(weights, 101, result.Containers) != nil {
+		t.Error("invalid packing:", result.Containers)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	result, err = SimulatedAnnealingContext(ctx, weights, 101, options)
+	if err != context.Canceled || result.Iterations != 0 {
+		t.Error("error:", err, "| iterations:", result.Iterations)
+	}
+}
+
+func TestSimulatedAnnealingObserver(t *testing.T) {
+	var progress []Progress
+	options := AnnealOptions{Temperature: 1.0, CoolingRate: 0.5, Steps: 50, StagnationLimit: 3, Seed: 7}
+	options.Observer = ObserverFunc(func(p Progress) {
+		progress = append(progress, p)
+	})
+	result, err := SimulatedAnnealing(exampleWeights, 150, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress) != result.Epochs {
+		t.Fatal("observed:", len(progress), "| epochs:", result.Epochs)
+	}
+	last := progress[len(progress)-1]
+	if last.Epoch != result.Epochs || last.Stagnation != options.StagnationLimit ||
+		last.BestEnergy != result.Energy || last.BestBins != len(result.Containers) {
+		t.Error("last progress:", last, "| result energy:", result.Energy)
+	}
+	for i, p := range progress {
+		if p.BestBins > p.Bins || (i > 0 && p.Temperature >= progress[i-1].Temperature) {
+			t.Error("progress:", p)
+		}
+	}
+}
+
+func TestSimulatedAnnealingBest(t *testing.T) {
+	// высокая температура: принимаются почти все ухудшения,
+	// поэтому итоговое текущее решение может быть хуже лучшего
+	weights := []int{ 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40 }
+	options := AnnealOptions{Temperature: 1000.0, CoolingRate: 0.9, Steps: 50, StagnationLimit: 3, Seed: 3,
+		TimeLimit: 100 * time.Millisecond}
+	var last Progress
+	options.Observer = ObserverFunc(func(p Progress) {
+		last = p
+	})
+	result, err := SimulatedAnnealing(weights, 101, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial := bestFit(weights, 101)
+	if len(result.Containers) > len(initial) || len(result.Containers) > last.Bins {
+		t.Error("best:", len(result.Containers), "| initial:", len(initial), "| last:", last.Bins)
+	}
+	if result.BestEpoch < 0 || result.BestEpoch > result.Epochs+1 {
+		t.Error("best epoch:", result.BestEpoch, "| epochs:", result.Epochs)
+	}
+	if result.Energy != float64(calculateUnfilledContainers(result.Containers, 101)) {
+		t.Error("energy:", result.Energy)
+	}
+	if Validate(weights, 101, result.Containers) != nil {
+		t.Error("invalid packing:", result.Containers)
+	}
+}
+
+func TestSimulatedAnnealingEnergy(t *testing.T) {
+	weights := []int{ 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38, 40 }
+	for _, energy := range []Energy{ FillSquaredEnergy{}, BinsSlackEnergy{} } {
+		options := AnnealOptions{Temperature: 0.01, CoolingRate: 0.9, Steps: 50, StagnationLimit: 3, Seed: 5,
+			Energy: energy, TimeLimit: 50 * time.Millisecond}
+		result, err := SimulatedAnnealing(weights, 101, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Energy != energy.Evaluate(result.Containers, 101) {
+			t.Error(energy, "energy:", result.Energy)
+		}
+		if Validate(weights, 101, result.Containers) != nil {
+			t.Error("invalid packing:", result.Containers)
+		}
+	}
+}
+
+func TestBestLog(t *testing.T) {
+	// лучшее решение из журнала совпадает с копией, сделанной в момент
+	// улучшения, в том числе после продвижения снимка и сброса журнала
+	rng := rand.New(rand.NewSource(4))
+	for _, every := range []int{ 3, 50, 1000 } {
+		solution := bestFit(exampleWeights, 150)
+		log := newBestLog(solution)
+		expected := createCopy(solution)
+		for i := 1; i <= 2000; i++ {
+			change, ok := propose(defaultMoves, solution, 150, rng)
+			if !ok {
+				continue
+			}
+			solution = change.apply(solution)
+			log.record(change)
+			if i%every == 0 {
+				log.mark(solution)
+				expected = createCopy(solution)
+			}
+		}
+		if result := log.solution(); !areEqual(result, expected) || Validate(exampleWeights, 150, result) != nil {
+			t.Error("every:", every, "| result:", result, "| expected:", expected)
+		}
+	}
+}
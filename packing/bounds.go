@@ -0,0 +1,188 @@
+package packing
+
+import (
+	"sort"
+)
+
+// ceilDiv - Целочисленное деление с округлением вверх
+// для неотрицательных a и положительных b
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// continuousBound - Непрерывная нижняя оценка (L1): ceil(сумма / W)
+func continuousBound(weights []int, capacity int) int {
+	sum := 0
+	for _, weight := range weights {
+		sum += weight
+	}
+	if sum <= 0 || capacity <= 0 {
+		return 0
+	}
+	return ceilDiv(sum, capacity)
+}
+
+/*
+	martelloTothBound
+	Нижняя оценка Мартелло-Тота (L2)
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		минимальное число контейнеров, которое гарантированно
+		понадобится для упаковки предметов
+*/
+func martelloTothBound(weights []int, capacity int) int {
+	n := len(weights)
+	if n == 0 || capacity <= 0 {
+		return 0
+	}
+
+	// веса по неубыванию и их префиксные суммы
+	sorted := make([]int, n)
+	copy(sorted, weights)
+	sort.Ints(sorted)
+	prefix := make([]int, n+1)
+	for i, weight := range sorted {
+		prefix[i+1] = prefix[i] + weight
+	}
+	// количество предметов с весом не больше x
+	count := func(x int) int {
+		return sort.SearchInts(sorted, x+1)
+	}
+
+	half := capacity / 2
+	bound := continuousBound(weights, capacity)
+
+	// перебираем все различные значения параметра alpha
+	// из множества {0} и весов, не превышающих W/2
+	previous := -1
+	for i := -1; i < n; i++ {
+		alpha := 0
+		if i >= 0 {
+			alpha = sorted[i]
+		}
+		if alpha > half {
+			break
+		}
+		if alpha == previous {
+			continue
+		}
+		previous = alpha
+
+		// J1 - предметы, с которыми не влезает ни один предмет из J3,
+		// J2 - остальные "большие" предметы, J3 - предметы [alpha, W/2]
+		j12 := count(capacity - alpha)
+		j1Count := n - j12
+		j2Count := j12 - count(half)
+		j2Sum := prefix[j12] - prefix[count(half)]
+		j3Sum := prefix[count(half)] - prefix[count(alpha-1)]
+
+		value := j1Count + j2Count
+		// место, оставшееся в контейнерах с предметами из J2
+		free := j2Count*capacity - j2Sum
+		if j3Sum > free {
+			value += ceilDiv(j3Sum-free, capacity)
+		}
+		if value > bound {
+			bound = value
+		}
+	}
+	return bound
+}
+
+// Bounds - Нижние оценки числа контейнеров
+type Bounds struct {
+	// L1 - непрерывная оценка ceil(сумма весов / W)
+	L1 int
+	// L2 - оценка Мартелло-Тота
+	L2 int
+	// L3 - оценка Мартелло-Тота, усиленная процедурой сокращения
+	L3 int
+}
+
+// Best - Возвращает наибольшую (самую точную) из оценок
+func (bounds Bounds) Best() int {
+	best := bounds.L1
+	if bounds.L2 > best {
+		best = bounds.L2
+	}
+	if bounds.L3 > best {
+		best = bounds.L3
+	}
+	return best
+}
+
+// Gap - Разница между числом контейнеров решения и лучшей оценкой
+func (bounds Bounds) Gap(solution Solution) int {
+	return solution.NumBins() - bounds.Best()
+}
+
+// reductionSteps - Наибольшее число удалений наименьшего предмета
+// при вычислении оценки L3: каждое удаление требует повторного
+// сокращения и вычисления L2, поэтому без ограничения оценка
+// вычисляется за O(n^2 log n)
+const reductionSteps = 10
+
+/*
+	reductionBound
+	Нижняя оценка L3: процедура сокращения чередуется с удалением
+	наименьшего предмета (не более reductionSteps раз и пока
+	сокращение фиксирует новые контейнеры), для каждой полученной
+	задачи вычисляется число зафиксированных контейнеров плюс
+	оценка L2 остатка
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		нижняя оценка числа контейнеров
+*/
+func reductionBound(weights []int, capacity int) int {
+	if capacity <= 0 {
+		return 0
+	}
+
+	// число контейнеров, зафиксированных на всех шагах
+	fixedCount := 0
+	bound := 0
+	rest := sortItemsDecreasing(NewItems(weights))
+	for step := 0; step <= reductionSteps && len(rest) > 0; step++ {
+		var fixed []Container
+		fixed, rest = reduce(rest, capacity)
+		if step > 0 && len(fixed) == 0 {
+			// без новых зафиксированных контейнеров оценка
+			// ослабленной задачи не больше предыдущей
+			break
+		}
+		fixedCount += len(fixed)
+
+		value := fixedCount + martelloTothBound(itemWeights(rest), capacity)
+		if value > bound {
+			bound = value
+		}
+
+		// удаление наименьшего предмета ослабляет задачу,
+		// поэтому любая её оценка остаётся оценкой исходной
+		if len(rest) > 0 {
+			rest = rest[:len(rest)-1]
+		}
+	}
+	return bound
+}
+
+/*
+	LowerBound
+	Вычисление нижних оценок числа контейнеров L1, L2 и L3
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		нижние оценки
+*/
+func LowerBound(weights []int, capacity int) Bounds {
+	return Bounds{
+		L1: continuousBound(weights, capacity),
+		L2: martelloTothBound(weights, capacity),
+		L3: reductionBound(weights, capacity),
+	}
+}
@@ -0,0 +1,64 @@
+package packing
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLowerBound(t *testing.T) {
+	samples := []struct {
+		weights []int
+		capacity int
+		bounds Bounds
+	}{
+		{
+			nil,
+			10,
+			Bounds{ L1: 0, L2: 0, L3: 0 },
+		}, {
+			[]int{ 1, 2, 3, 4 },
+			10,
+			Bounds{ L1: 1, L2: 1, L3: 1 },
+		}, {
+			[]int{ 6, 6, 6 },
+			10,
+			Bounds{ L1: 2, L2: 3, L3: 3 },
+		}, {
+			[]int{ 7, 7, 4, 4, 4, 4 },
+			10,
+			Bounds{ L1: 3, L2: 4, L3: 4 },
+		}, {
+			// L2 не учитывает, что никакие три предмета 4
+			// не влезают в один контейнер
+			[]int{ 4, 4, 4, 4, 4, 4, 4 },
+			10,
+			Bounds{ L1: 3, L2: 3, L3: 4 },
+		},
+	}
+
+	for _, sample := range samples {
+		expected := sample.bounds
+		result := LowerBound(sample.weights, sample.capacity)
+		if result != expected {
+			t.Error("weights:", sample.weights, "| result:", result, "| expected:", expected)
+		}
+	}
+}
+
+func TestLowerBoundRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for iteration := 0; iteration < 200; iteration++ {
+		capacity := 10 + rng.Intn(20)
+		weights := make([]int, 1+rng.Intn(9))
+		for i := range weights {
+			weights[i] = 1 + rng.Intn(capacity)
+		}
+
+		optimum := bruteForce(weights, capacity)
+		bounds := LowerBound(weights, capacity)
+		if bounds.Best() > optimum || bounds.L1 > bounds.L2 {
+			t.Error("weights:", weights, "| capacity:", capacity,
+				"| bounds:", bounds, "| optimum:", optimum)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package packing
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Calibration - Параметры автоматического подбора начальной температуры
+type Calibration struct {
+	// Acceptance - доля ухудшающих решений, которые должны приниматься
+	// при начальной температуре, например 0.8 (0 - подбор отключён)
+	Acceptance float64
+	// Samples - число пробных решений (по умолчанию 500)
+	Samples int
+}
+
+/*
+	calibrateTemperature
+	Подбор начальной температуры: из начального решения строятся
+	пробные соседние решения, и температура выбирается так, чтобы
+	средняя вероятность принятия ухудшающих из них равнялась заданной
+	входные данные:
+		solution - начальное решение
+		capacity - вместимость контейнеров
+		objective - функция энергии
+		moves - операторы окрестности
+		calibration - параметры подбора
+		rng - источник случайных чисел
+	выходные данные:
+		подобранная температура; false, если среди пробных решений
+		не оказалось ухудшающих и температуру подобрать нельзя
+*/
+func calibrateTemperature(solution []Container, capacity int, objective Energy, moves []WeightedMove,
+	calibration Calibration, rng *rand.Rand) (float64, bool) {
+	samples := calibration.Samples
+	if samples <= 0 {
+		samples = 500
+	}
+	acceptance := math.Min(calibration.Acceptance, 1-1e-9)
+
+	energies := newEvaluator(objective, solution, capacity)
+	var deltas []float64
+	for i := 0; i < samples; i++ {
+		change, ok := propose(moves, solution, capacity, rng)
+		if !ok {
+			continue
+		}
+		energy, _ := energies.propose(solution, change)
+		if delta := energy - energies.energy; delta > 0 {
+			deltas = append(deltas, delta)
+		}
+	}
+	if len(deltas) == 0 {
+		return 0, false
+	}
+
+	// средняя вероятность принятия ухудшений при температуре T
+	rate := func(T float64) float64 {
+		var sum float64
+		for _, delta := range deltas {
+			sum += math.Exp(-delta / T)
+		}
+		return sum / float64(len(deltas))
+	}
+
+	// вероятность монотонно растёт с температурой, поэтому
+	// температура находится делением отрезка пополам; начальное
+	// приближение - формула Киркпатрика для среднего ухудшения
+	var mean float64
+	for _, delta := range deltas {
+		mean += delta
+	}
+	mean /= float64(len(deltas))
+	guess := -mean / math.Log(acceptance)
+
+	low, high := guess, guess
+	for rate(low) > acceptance {
+		low /= 2
+	}
+	for rate(high) < acceptance {
+		high *= 2
+	}
+	for i := 0; i < 100 && high-low > 1e-12*high; i++ {
+		middle := (low + high) / 2
+		if rate(middle) < acceptance {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2, true
+}
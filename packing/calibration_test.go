@@ -0,0 +1,80 @@
+package packing
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bins - Энергия, равная числу контейнеров
+var bins = EnergyFunc(func(containers []Container, capacity int) float64 {
+	return float64(len(containers))
+})
+
+func TestCalibrateTemperature(t *testing.T) {
+	solution := []Container{
+		fromWeights(1),
+	}
+	// каждое пробное решение хуже текущего ровно на 1
+	worse := []WeightedMove{
+		WeightedMove{ Move: MoveFunc(func(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+			return Change{ Indices: []int{ -1 }, Contents: []Container{ fromWeights(0) } }, true
+		}), Weight: 1 },
+	}
+	T, ok := calibrateTemperature(solution, 10, bins, worse, Calibration{ Acceptance: 0.8 }, rand.New(rand.NewSource(1)))
+	expected := -1 / math.Log(0.8)
+	if !ok || math.Abs(T-expected) > 1e-6 {
+		t.Error("result:", T, ok, "| expected:", expected)
+	}
+
+	// ухудшающих решений нет
+	same := []WeightedMove{
+		WeightedMove{ Move: MoveFunc(func(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+			return Change{}, false
+		}), Weight: 1 },
+	}
+	if _, ok := calibrateTemperature(solution, 10, bins, same, Calibration{ Acceptance: 0.8 }, rand.New(rand.NewSource(1))); ok {
+		t.Error("expected calibration failure")
+	}
+
+	options := AnnealOptions{Temperature: 7, Steps: 1, StagnationLimit: 1, Moves: same,
+		Calibration: Calibration{ Acceptance: 0.8 }}
+	if result, err := SimulatedAnnealing([]int{ 1 }, 10, options); err != nil || result.Temperature != 7 {
+		t.Error("temperature:", result.Temperature, "| expected:", 7)
+	}
+}
+
+func TestCalibrateTemperatureAcceptance(t *testing.T) {
+	// при подобранной температуре доля принятых ухудшений
+	// среди пробных решений близка к заданной
+	solution := bestFit(exampleWeights, 150)
+	moves := []WeightedMove{ WeightedMove{ Move: EjectMove{}, Weight: 1 } }
+	for _, acceptance := range []float64{ 0.3, 0.8 } {
+		rng := rand.New(rand.NewSource(2))
+		T, ok := calibrateTemperature(solution, 150, FillSquaredEnergy{}, moves,
+			Calibration{ Acceptance: acceptance, Samples: 200 }, rng)
+		if !ok {
+			t.Fatal("calibration failed")
+		}
+
+		rng = rand.New(rand.NewSource(2))
+		objective := FillSquaredEnergy{}
+		energy := objective.Evaluate(solution, 150)
+		var sum float64
+		var count int
+		for i := 0; i < 200; i++ {
+			change, ok := propose(moves, solution, 150, rng)
+			if !ok {
+				continue
+			}
+			another := change.apply(createCopy(solution))
+			if delta := objective.Evaluate(another, 150) - energy; delta > 0 {
+				sum += math.Exp(-delta / T)
+				count++
+			}
+		}
+		if math.Abs(sum/float64(count)-acceptance) > 1e-6 {
+			t.Error("acceptance:", sum/float64(count), "| expected:", acceptance)
+		}
+	}
+}
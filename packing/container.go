@@ -2,30 +2,65 @@ package packing
 
 // Container - Представляет собой контейнер с предметами
 type Container struct {
-	weights []int
+	items []Item
+	// load - сумма весов, поддерживаемая при добавлении
+	// и удалении предметов
+	load int
 }
 
 // New - Возвращает новый контейнер
 func New() Container {
-	return Container{weights: nil}
+	return Container{items: nil}
 }
 
-// getSum - Вычисляет сумму весов контейнера
+// Load - Возвращает сумму весов предметов в контейнере
+func (container Container) Load() int {
+	return container.load
+}
+
+// Items - Возвращает копию предметов в контейнере
+func (container Container) Items() []Item {
+	return append([]Item(nil), container.items...)
+}
+
+// IDs - Возвращает идентификаторы предметов в контейнере
+func (container Container) IDs() []int {
+	ids := make([]int, len(container.items))
+	for i, item := range container.items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+// Weights - Возвращает веса предметов в контейнере
+func (container Container) Weights() []int {
+	return itemWeights(container.items)
+}
+
+// Len - Возвращает количество предметов в контейнере
+func (container Container) Len() int {
+	return len(container.items)
+}
+
+// getSum - Вычисляет сумму весов контейнера заново (в отличие
+// от Load, которая возвращает сохранённое значение)
 func (container Container) getSum() int {
 	sum := 0
-	for _, weight := range container.weights {
-		sum += weight
+	for _, item := range container.items {
+		sum += item.Weight
 	}
 	return sum
 }
 
+// isEqual - Сравнивает контейнеры по весам предметов: перестановка
+// предметов одинакового веса упаковку не меняет
 func (container Container) isEqual(anotherContainer Container) bool {
-	if len(container.weights) != len(anotherContainer.weights) {
+	if len(container.items) != len(anotherContainer.items) {
 		return false
 	}
 
-	for i, weight := range container.weights {
-		if weight != anotherContainer.weights[i] {
+	for i, item := range container.items {
+		if item.Weight != anotherContainer.items[i].Weight {
 			return false
 		}
 	}
@@ -45,12 +80,13 @@ func areEqual(first, second []Container) bool {
 	return true
 }
 
-// Добавляет вес в контейнер
-func (container *Container) append(weight int) {
-	container.weights = append(container.weights, weight)
+// Добавляет предмет в контейнер
+func (container *Container) append(item Item) {
+	container.items = append(container.items, item)
+	container.load += item.Weight
 }
 
 // GetPadding - Вычисляет размер оставшегося места в контейнере
 func (container Container) GetPadding(capacity int) int {
-	return capacity - container.getSum()
+	return capacity - container.load
 }
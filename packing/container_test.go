@@ -1,6 +1,7 @@
 package packing
 
 import (
+	"math/rand"
 	"testing"
 )
 
@@ -11,20 +12,20 @@ func TestIsEqual(t *testing.T) {
 		areEqual bool
 	}{
 		{
-			Container{ weights: nil },
-			Container{ weights: nil },
+			Container{ items: nil },
+			Container{ items: nil },
 			true,
 		}, {
-			Container{ weights: []int{ 1 } },
-			Container{ weights: []int{} },
+			fromWeights(1),
+			Container{ items: []Item{} },
 			false,
 		}, {
-			Container{ weights: []int{ 1, 2, 3, 4 } },
-			Container{ weights: []int{ 4, 3, 2, 1 } },
+			fromWeights(1, 2, 3, 4),
+			fromWeights(4, 3, 2, 1),
 			false,
 		}, {
-			Container{ weights: []int{} },
-			Container{ weights: []int{} },
+			Container{ items: []Item{} },
+			Container{ items: []Item{} },
 			true,
 		},
 	}
@@ -50,36 +51,36 @@ func TestAreEqual(t *testing.T) {
 			true,
 		}, {
 			[]Container{
-				Container{ weights: nil },
-				Container{ weights: nil }, 
+				Container{ items: nil },
+				Container{ items: nil }, 
 			},
 			[]Container{
-				Container{ weights: nil },
-				Container{ weights: nil },
+				Container{ items: nil },
+				Container{ items: nil },
 			},
 			true,
 		}, {
 			[]Container{},
 			[]Container{
-				Container{ weights: nil },
+				Container{ items: nil },
 			},
 			false,
 		}, {
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3, 4 } },
-				Container{ weights: []int{ 5, 6, 7, 8 } }, 
+				fromWeights(1, 2, 3, 4),
+				fromWeights(5, 6, 7, 8), 
 			},
 			[]Container{
-				Container{ weights: []int{ 1, 2, 3, 4 } },
-				Container{ weights: []int{ 5, 6, 7, 8 } }, 
+				fromWeights(1, 2, 3, 4),
+				fromWeights(5, 6, 7, 8), 
 			},
 			true,
 		}, {
 			[]Container{
-				Container{ weights: []int{ 1 } },
+				fromWeights(1),
 			},
 			[]Container{
-				Container{ weights: []int{ 2 } },
+				fromWeights(2),
 			},
 			false,
 		},
@@ -99,16 +100,16 @@ func TestGetSum(t *testing.T) {
 		sum int
 	}{
 		{
-			Container{ weights: nil },
+			Container{ items: nil },
 			0,
 		}, {
-			Container{ weights: []int{} },
+			Container{ items: []Item{} },
 			0,
 		}, {
-			Container{ weights: []int{ 1, 2, 3, 4 } },
+			fromWeights(1, 2, 3, 4),
 			10,
 		}, {
-			Container{ weights: []int{ 0, 0, 0, 0, 0 } },
+			fromWeights(0, 0, 0, 0, 0),
 			0,
 		},
 	}
@@ -129,23 +130,23 @@ func TestGetPadding(t *testing.T) {
 		padding int
 	} {
 		{
-			Container{ weights: []int{ 1, 2, 3, 7 } },
+			fromWeights(1, 2, 3, 7),
 			15,
 			2,
 		}, {
-			Container{ weights: []int{ 1, 2, 3 } },
+			fromWeights(1, 2, 3),
 			10,
 			4,
 		}, {
-			Container{ weights: []int{} },
+			Container{ items: []Item{} },
 			10,
 			10,
 		}, {
-			Container{ weights: []int{ 1, 2, 3 } },
+			fromWeights(1, 2, 3),
 			6,
 			0,
 		}, {
-			Container{ weights: []int{ 1, 2, 3 } },
+			fromWeights(1, 2, 3),
 			1,
 			-5,
 		},
@@ -158,4 +159,99 @@ func TestGetPadding(t *testing.T) {
 			t.Error("result:", result, "| expected:", expected)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// fromWeights - Возвращает контейнер с заданными весами предметов;
+// идентификатор предмета - его индекс в контейнере
+func fromWeights(weights ...int) Container {
+	container := New()
+	for _, item := range NewItems(weights) {
+		container.append(item)
+	}
+	return container
+}
+
+// renumber - Присваивает предметам сквозные идентификаторы в порядке
+// обхода контейнеров, как NewItems для весов в том же порядке
+func renumber(containers ...Container) []Container {
+	id := 0
+	for _, container := range containers {
+		for i := range container.items {
+			container.items[i].ID = id
+			id++
+		}
+	}
+	return containers
+}
+
+// consistentLoads - Проверяет, что сохранённая загрузка каждого
+// контейнера совпадает с суммой весов его предметов
+func consistentLoads(containers []Container) bool {
+	for _, container := range containers {
+		if container.Load() != container.getSum() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLoad(t *testing.T) {
+	container := fromWeights(5, 3, 7, 1)
+	if container.Load() != 16 {
+		t.Error("result:", container.Load(), "| expected:", 16)
+	}
+	// загрузка пересчитывается при изменении контейнера
+	container = modified(container, []int{ 1 }, Item{ ID: 4, Weight: 2 })
+	if container.Load() != 15 || !consistentLoads([]Container{ container }) {
+		t.Error("result:", container.Load(), "| expected:", 15)
+	}
+	container = modified(container, []int{ 0, 1, 2, 3 })
+	if container.Load() != 0 {
+		t.Error("result:", container.Load(), "| expected:", 0)
+	}
+}
+
+func TestLoadConsistency(t *testing.T) {
+	// загрузка остаётся согласованной после работы всех
+	// алгоритмов и применения всех операторов окрестности
+	algorithms := map[string]func([]int, int) (Solution, error){
+		"BestFit":            BestFit,
+		"NextFit":            NextFit,
+		"FirstFit":           FirstFit,
+		"WorstFit":           WorstFit,
+		"AlmostWorstFit":     AlmostWorstFit,
+		"FirstFitDecreasing": FirstFitDecreasing,
+		"BestFitDecreasing":  BestFitDecreasing,
+		"Exact": func(weights []int, capacity int) (Solution, error) {
+			result, err := Exact(weights, capacity, ExactOptions{ NodeLimit: 1000 })
+			return result.Solution, err
+		},
+		"SimulatedAnnealing": func(weights []int, capacity int) (Solution, error) {
+			options := DefaultAnnealOptions()
+			options.Moves = []WeightedMove{ WeightedMove{ Move: EjectMove{}, Weight: 1 } }
+			result, err := SimulatedAnnealing(weights, capacity, options)
+			return result.Solution, err
+		},
+	}
+	for name, algorithm := range algorithms {
+		result, err := algorithm(exampleWeights, 150)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if !consistentLoads(result.Containers) {
+			t.Error(name, "inconsistent loads:", result)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(5))
+	moves := []Move{ ShiftMove{}, SwapMove{}, Swap21Move{}, Swap22Move{}, EjectMove{}, ChainMove{} }
+	for _, move := range moves {
+		solution := bestFit(exampleWeights, 150)
+		for i := 0; i < 200; i++ {
+			solution = neighbour(move, solution, 150, rng)
+			if !consistentLoads(solution) {
+				t.Fatal(move, "step:", i, "| inconsistent loads:", solution)
+			}
+		}
+	}
+}
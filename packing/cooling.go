@@ -0,0 +1,146 @@
+package packing
+
+import (
+	"math"
+)
+
+// CoolingState - Состояние отжига в конце очередной смены температуры
+type CoolingState struct {
+	// Initial - начальная температура
+	Initial float64
+	// Temperature - текущая температура
+	Temperature float64
+	// Epoch - число завершённых смен температуры (начиная с 1)
+	Epoch int
+	// AcceptanceRate - доля принятых решений при текущей температуре
+	AcceptanceRate float64
+}
+
+// CoolingSchedule - Закон изменения температуры
+type CoolingSchedule interface {
+	// Next - возвращает температуру для следующей смены
+	Next(state CoolingState) float64
+}
+
+// GeometricCooling - Геометрическое охлаждение: T = T * r
+type GeometricCooling struct {
+	// Rate - коэффициент охлаждения (r)
+	Rate float64
+}
+
+// Next - Возвращает следующую температуру
+func (cooling GeometricCooling) Next(state CoolingState) float64 {
+	return state.Temperature * cooling.Rate
+}
+
+// LinearCooling - Линейное охлаждение: T = T - step, но не ниже нуля
+type LinearCooling struct {
+	// Step - величина, на которую уменьшается температура
+	Step float64
+	// Rate - если Step не задан, то шаг равен Initial * (1 - Rate):
+	// первая смена температуры совпадает с геометрическим
+	// охлаждением при фактической начальной температуре
+	Rate float64
+}
+
+// Next - Возвращает следующую температуру
+func (cooling LinearCooling) Next(state CoolingState) float64 {
+	step := cooling.Step
+	if step == 0 {
+		step = state.Initial * (1 - cooling.Rate)
+	}
+	return math.Max(state.Temperature-step, 0)
+}
+
+// LogarithmicCooling - Логарифмическое охлаждение Хайека:
+// T(k) = C / ln(k + 2), где k - номер смены температуры, а C
+// выбирается так, чтобы T(0) совпадала с начальной температурой.
+// Следующая температура вычисляется из текущей:
+// T = T * ln(k + 1) / ln(k + 2), поэтому после повторного
+// нагрева охлаждение продолжается от новой температуры
+type LogarithmicCooling struct{}
+
+// Next - Возвращает следующую температуру
+func (LogarithmicCooling) Next(state CoolingState) float64 {
+	k := float64(state.Epoch)
+	if k < 1 {
+		k = 1
+	}
+	return state.Temperature * math.Log(k+1) / math.Log(k+2)
+}
+
+// LundyMeesCooling - Охлаждение Ланди-Миса: T = T / (1 + beta * T)
+type LundyMeesCooling struct {
+	Beta float64
+	// Rate - если Beta не задан, то beta = (1 - Rate) / (Rate * Initial):
+	// первая смена температуры совпадает с геометрическим
+	// охлаждением при фактической начальной температуре
+	Rate float64
+}
+
+// Next - Возвращает следующую температуру
+func (cooling LundyMeesCooling) Next(state CoolingState) float64 {
+	beta := cooling.Beta
+	if beta == 0 && cooling.Rate > 0 && state.Initial > 0 {
+		beta = (1 - cooling.Rate) / (cooling.Rate * state.Initial)
+	}
+	return state.Temperature / (1 + beta*state.Temperature)
+}
+
+// AdaptiveCooling - Адаптивное охлаждение: пока доля принятых решений
+// выше целевой, температура понижается быстрее геометрической,
+// а когда ниже - медленнее: T = T * r^(доля / целевая доля)
+type AdaptiveCooling struct {
+	// Target - целевая доля принятых решений (0, 1]
+	Target float64
+	// Rate - коэффициент охлаждения при доле, равной целевой
+	Rate float64
+}
+
+// Next - Возвращает следующую температуру
+func (cooling AdaptiveCooling) Next(state CoolingState) float64 {
+	if cooling.Target <= 0 {
+		return state.Temperature * cooling.Rate
+	}
+	// показатель ограничен, чтобы температура не падала скачком
+	exponent := math.Min(state.AcceptanceRate/cooling.Target, 2)
+	return state.Temperature * math.Pow(cooling.Rate, exponent)
+}
+
+// Reheating - Правило повторного нагрева: когда число смен
+// температуры без изменения решения достигает порога,
+// температура поднимается, а счётчик обнуляется
+type Reheating struct {
+	// Threshold - порог счётчика (0 - нагрев отключён);
+	// имеет смысл, только если он меньше StagnationLimit
+	Threshold int
+	// Ratio - новая температура в долях от начальной
+	// (0 - DefaultReheatRatio)
+	Ratio float64
+	// Limit - наибольшее число нагревов (0 - DefaultReheatLimit)
+	Limit int
+}
+
+// Значения параметров повторного нагрева по умолчанию
+const (
+	DefaultReheatRatio = 0.5
+	DefaultReheatLimit = 3
+)
+
+// due - Проверяет, нужно ли нагревать при счётчике p,
+// если нагрев уже производился reheats раз
+func (reheating Reheating) due(p, reheats int) bool {
+	limit := reheating.Limit
+	if limit == 0 {
+		limit = DefaultReheatLimit
+	}
+	return reheating.Threshold > 0 && p >= reheating.Threshold && reheats < limit
+}
+
+// temperature - Возвращает температуру после нагрева
+func (reheating Reheating) temperature(initial float64) float64 {
+	if reheating.Ratio == 0 {
+		return initial * DefaultReheatRatio
+	}
+	return initial * reheating.Ratio
+}
@@ -0,0 +1,137 @@
+package packing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCoolingSchedules(t *testing.T) {
+	state := CoolingState{ Initial: 100, Temperature: 50, Epoch: 1, AcceptanceRate: 0.2 }
+	samples := []struct {
+		name string
+		cooling CoolingSchedule
+		temperature float64
+	}{
+		{
+			"geometric",
+			GeometricCooling{ Rate: 0.9 },
+			45,
+		}, {
+			"linear",
+			LinearCooling{ Step: 20 },
+			30,
+		}, {
+			"linear",
+			LinearCooling{ Step: 80 },
+			0,
+		}, {
+			// шаг 100 * (1 - 0.9) от начальной температуры
+			"linear",
+			LinearCooling{ Rate: 0.9 },
+			40,
+		}, {
+			"logarithmic",
+			LogarithmicCooling{},
+			50 * math.Ln2 / math.Log(3),
+		}, {
+			"lundy-mees",
+			LundyMeesCooling{ Beta: 0.01 },
+			50.0 / 1.5,
+		}, {
+			// beta = (1 - 0.5) / (0.5 * 100)
+			"lundy-mees",
+			LundyMeesCooling{ Rate: 0.5 },
+			50.0 / 1.5,
+		}, {
+			// доля принятых решений вдвое меньше целевой
+			"adaptive",
+			AdaptiveCooling{ Target: 0.4, Rate: 0.81 },
+			45,
+		},
+	}
+
+	for _, sample := range samples {
+		result := sample.cooling.Next(state)
+		if math.Abs(result-sample.temperature) > 1e-9 {
+			t.Error(sample.name, "result:", result, "| expected:", sample.temperature)
+		}
+	}
+}
+
+func TestLogarithmicCooling(t *testing.T) {
+	// без нагрева температура равна C / ln(k + 2), C = T(0) * ln 2
+	T := 100.0
+	for k := 1; k <= 10; k++ {
+		T = LogarithmicCooling{}.Next(CoolingState{ Initial: 100, Temperature: T, Epoch: k })
+		if expected := 100 * math.Ln2 / math.Log(float64(k)+2); math.Abs(T-expected) > 1e-9 {
+			t.Fatal("epoch:", k, "| result:", T, "| expected:", expected)
+		}
+	}
+
+	// после нагрева охлаждение продолжается от новой температуры
+	result := LogarithmicCooling{}.Next(CoolingState{ Initial: 100, Temperature: 50, Epoch: 10 })
+	if expected := 50 * math.Log(11) / math.Log(12); math.Abs(result-expected) > 1e-9 {
+		t.Error("result:", result, "| expected:", expected)
+	}
+}
+
+func TestReheating(t *testing.T) {
+	// решение не меняется ни при какой температуре, поэтому
+	// каждая смена температуры увеличивает счётчик
+	initial := renumber(
+		fromWeights(3, 7),
+		fromWeights(7, 3),
+	)
+	var temperatures []float64
+	options := AnnealOptions{Temperature: 8, Steps: 5, StagnationLimit: 2, InitialSolution: initial,
+		Cooling: GeometricCooling{ Rate: 0.5 }, Reheating: Reheating{ Threshold: 1, Ratio: 0.5, Limit: 2 }}
+	options.Observer = ObserverFunc(func(p Progress) {
+		temperatures = append(temperatures, p.Temperature)
+	})
+	result, err := SimulatedAnnealing([]int{ 3, 7, 7, 3 }, 10, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// два нагрева до 4, затем охлаждение до остановки
+	expected := []float64{ 4, 4, 2, 1 }
+	if result.Reheats != 2 || len(temperatures) != len(expected) {
+		t.Fatal("reheats:", result.Reheats, "| temperatures:", temperatures)
+	}
+	for i := range expected {
+		if temperatures[i] != expected[i] {
+			t.Error("temperatures:", temperatures, "| expected:", expected)
+			break
+		}
+	}
+}
+
+func TestReheatingDefaults(t *testing.T) {
+	// без Ratio и Limit нагрев производится DefaultReheatLimit раз
+	// до DefaultReheatRatio от начальной температуры
+	initial := renumber(
+		fromWeights(3, 7),
+		fromWeights(7, 3),
+	)
+	var temperatures []float64
+	options := AnnealOptions{Temperature: 8, Steps: 5, StagnationLimit: 2, InitialSolution: initial,
+		Cooling: GeometricCooling{ Rate: 0.5 }, Reheating: Reheating{ Threshold: 1 }}
+	options.Observer = ObserverFunc(func(p Progress) {
+		temperatures = append(temperatures, p.Temperature)
+	})
+	result, err := SimulatedAnnealing([]int{ 3, 7, 7, 3 }, 10, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []float64{ 4, 4, 4, 2, 1 }
+	if result.Reheats != DefaultReheatLimit || len(temperatures) != len(expected) {
+		t.Fatal("reheats:", result.Reheats, "| temperatures:", temperatures)
+	}
+	for i := range expected {
+		if temperatures[i] != expected[i] {
+			t.Error("temperatures:", temperatures, "| expected:", expected)
+			break
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package packing
+
+// Energy - Функция энергии (целевая функция) алгоритма имитации
+// отжига: чем меньше значение, тем лучше решение
+type Energy interface {
+	Evaluate(containers []Container, capacity int) float64
+}
+
+// EnergyFunc - Позволяет использовать обычную функцию как Energy
+type EnergyFunc func(containers []Container, capacity int) float64
+
+// Evaluate - Вызывает саму функцию
+func (f EnergyFunc) Evaluate(containers []Container, capacity int) float64 {
+	return f(containers, capacity)
+}
+
+// EnergyState - Сводка решения, по которой вычисляется
+// энергия, разложимая на слагаемые по контейнерам
+type EnergyState struct {
+	// Bins - число контейнеров
+	Bins int
+	// Load - суммарная загрузка контейнеров
+	Load int
+	// Terms - сумма слагаемых всех контейнеров
+	Terms float64
+}
+
+// SeparableEnergy - Функция энергии, которая зависит только от суммы
+// слагаемых, вычисляемых по загрузке каждого контейнера, числа
+// контейнеров и их суммарной загрузки. Для таких функций изменение
+// энергии вычисляется только по изменённым контейнерам
+type SeparableEnergy interface {
+	Energy
+	// Term - слагаемое контейнера с загрузкой load
+	Term(load, capacity int) float64
+	// Combine - энергия решения по его сводке
+	Combine(state EnergyState, capacity int) float64
+}
+
+// summarize - Вычисляет сводку решения для разложимой функции энергии
+func summarize(objective SeparableEnergy, containers []Container, capacity int) EnergyState {
+	state := EnergyState{Bins: len(containers)}
+	for _, container := range containers {
+		load := container.Load()
+		state.Load += load
+		state.Terms += objective.Term(load, capacity)
+	}
+	return state
+}
+
+// UnfilledEnergy - Количество не заполненных до конца контейнеров
+type UnfilledEnergy struct{}
+
+// Evaluate - Вычисляет энергию решения
+func (UnfilledEnergy) Evaluate(containers []Container, capacity int) float64 {
+	return float64(calculateUnfilledContainers(containers, capacity))
+}
+
+// Term - Возвращает 1 для незаполненного контейнера
+func (UnfilledEnergy) Term(load, capacity int) float64 {
+	if load != capacity {
+		return 1
+	}
+	return 0
+}
+
+// Combine - Вычисляет энергию по сводке решения
+func (UnfilledEnergy) Combine(state EnergyState, capacity int) float64 {
+	return state.Terms
+}
+
+// FillSquaredEnergy - Функция приспособленности Фалькенауэра
+// f = sum((load / W)^2) / N, взятая с обратным знаком:
+// при том же числе контейнеров выгоднее наполнять одни
+// контейнеры за счёт других
+type FillSquaredEnergy struct{}
+
+// Evaluate - Вычисляет энергию решения
+func (energy FillSquaredEnergy) Evaluate(containers []Container, capacity int) float64 {
+	return energy.Combine(summarize(energy, containers, capacity), capacity)
+}
+
+// Term - Возвращает квадрат заполненности контейнера
+func (FillSquaredEnergy) Term(load, capacity int) float64 {
+	fill := float64(load) / float64(capacity)
+	return fill * fill
+}
+
+// Combine - Вычисляет энергию по сводке решения
+func (FillSquaredEnergy) Combine(state EnergyState, capacity int) float64 {
+	if state.Bins == 0 {
+		return 0
+	}
+	return 1 - state.Terms/float64(state.Bins)
+}
+
+// BinsSlackEnergy - Число контейнеров минус доля незаполненного
+// пространства, сосредоточенного в одних и тех же контейнерах:
+// N - sum(padding^2) / (W * sum(padding)). Вычитаемое меньше 1,
+// поэтому решение с меньшим числом контейнеров всегда лучше, а при
+// равном числе лучше то, где свободное место собрано в меньшем
+// количестве контейнеров, которые проще освободить
+type BinsSlackEnergy struct{}
+
+// Evaluate - Вычисляет энергию решения
+func (energy BinsSlackEnergy) Evaluate(containers []Container, capacity int) float64 {
+	return energy.Combine(summarize(energy, containers, capacity), capacity)
+}
+
+// Term - Возвращает квадрат незаполненного места в контейнере
+func (BinsSlackEnergy) Term(load, capacity int) float64 {
+	padding := float64(capacity - load)
+	return padding * padding
+}
+
+// Combine - Вычисляет энергию по сводке решения
+func (BinsSlackEnergy) Combine(state EnergyState, capacity int) float64 {
+	total := state.Bins*capacity - state.Load
+	if total <= 0 {
+		return float64(state.Bins)
+	}
+	return float64(state.Bins) - state.Terms/(float64(capacity)*float64(total))
+}
+
+// evaluator - Вычисляет энергию соседних решений. Для разложимых
+// функций энергии изменение вычисляется только по контейнерам,
+// которые затрагивает изменение, иначе изменение применяется
+// к копии решения и энергия вычисляется заново
+type evaluator struct {
+	objective Energy
+	separable SeparableEnergy
+	capacity  int
+	state     EnergyState
+	energy    float64
+}
+
+// newEvaluator - Создаёт вычислитель энергии для текущего решения
+func newEvaluator(objective Energy, containers []Container, capacity int) *evaluator {
+	e := &evaluator{objective: objective, capacity: capacity}
+	e.separable, _ = objective.(SeparableEnergy)
+	e.reset(containers)
+	return e
+}
+
+// reset - Заново вычисляет энергию текущего решения
+// (в том числе чтобы не накапливалась погрешность округления)
+func (e *evaluator) reset(containers []Container) {
+	if e.separable != nil {
+		e.state = summarize(e.separable, containers, e.capacity)
+		e.energy = e.separable.Combine(e.state, e.capacity)
+	} else {
+		e.energy = e.objective.Evaluate(containers, e.capacity)
+	}
+}
+
+// propose - Вычисляет энергию решения после изменения, не изменяя
+// само решение; вместе с энергией возвращается сводка нового решения
+func (e *evaluator) propose(containers []Container, change Change) (float64, EnergyState) {
+	if e.separable == nil {
+		another := change.apply(createCopy(containers))
+		return e.objective.Evaluate(another, e.capacity), EnergyState{}
+	}
+
+	state := e.state
+	for i, index := range change.Indices {
+		if index != -1 {
+			load := containers[index].Load()
+			state.Bins--
+			state.Load -= load
+			state.Terms -= e.separable.Term(load, e.capacity)
+		}
+		if load := change.Contents[i].Load(); change.Contents[i].Len() > 0 {
+			state.Bins++
+			state.Load += load
+			state.Terms += e.separable.Term(load, e.capacity)
+		}
+	}
+	return e.separable.Combine(state, e.capacity), state
+}
+
+// accept - Запоминает энергию и сводку принятого решения
+func (e *evaluator) accept(energy float64, state EnergyState) {
+	e.energy = energy
+	e.state = state
+}
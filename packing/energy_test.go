@@ -0,0 +1,100 @@
+package packing
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEnergies(t *testing.T) {
+	containers := []Container{
+		fromWeights(4, 6),
+		fromWeights(5),
+		fromWeights(2),
+	}
+	samples := []struct {
+		name string
+		energy Energy
+		value float64
+	}{
+		{
+			"unfilled",
+			UnfilledEnergy{},
+			2,
+		}, {
+			// (1 + 0.25 + 0.04) / 3
+			"fill-squared",
+			FillSquaredEnergy{},
+			1 - 1.29/3,
+		}, {
+			// 3 - (25 + 64) / (10 * 13)
+			"bins-slack",
+			BinsSlackEnergy{},
+			3 - 89.0/130.0,
+		}, {
+			"func",
+			EnergyFunc(func(containers []Container, capacity int) float64 {
+				return float64(len(containers) * capacity)
+			}),
+			30,
+		},
+	}
+
+	for _, sample := range samples {
+		result := sample.energy.Evaluate(containers, 10)
+		if math.Abs(result-sample.value) > 1e-9 {
+			t.Error(sample.name, "result:", result, "| expected:", sample.value)
+		}
+	}
+}
+
+func TestEnergiesPreferFewerBins(t *testing.T) {
+	// 2 контейнера лучше 3 при любой функции энергии
+	two := []Container{
+		fromWeights(5, 4),
+		fromWeights(3),
+	}
+	three := []Container{
+		fromWeights(5),
+		fromWeights(4),
+		fromWeights(3),
+	}
+	for _, energy := range []Energy{ FillSquaredEnergy{}, BinsSlackEnergy{} } {
+		if energy.Evaluate(two, 10) >= energy.Evaluate(three, 10) {
+			t.Error(energy, "prefers more bins")
+		}
+	}
+}
+
+func TestEvaluatorDelta(t *testing.T) {
+	// изменение энергии, вычисленное по затронутым контейнерам,
+	// совпадает с разностью энергий, вычисленных заново
+	moves := []WeightedMove{
+		WeightedMove{ Move: ShiftMove{}, Weight: 1 },
+		WeightedMove{ Move: SwapMove{}, Weight: 1 },
+		WeightedMove{ Move: Swap21Move{}, Weight: 1 },
+		WeightedMove{ Move: EjectMove{}, Weight: 1 },
+		WeightedMove{ Move: ChainMove{}, Weight: 1 },
+	}
+	for _, objective := range []Energy{ UnfilledEnergy{}, FillSquaredEnergy{}, BinsSlackEnergy{} } {
+		rng := rand.New(rand.NewSource(3))
+		solution := bestFit(exampleWeights, 150)
+		energies := newEvaluator(objective, solution, 150)
+		for i := 0; i < 500; i++ {
+			change, ok := propose(moves, solution, 150, rng)
+			if !ok {
+				continue
+			}
+			energy, state := energies.propose(solution, change)
+			expected := objective.Evaluate(change.apply(createCopy(solution)), 150)
+			if math.Abs(energy-expected) > 1e-9 {
+				t.Fatal(objective, "step:", i, "| result:", energy, "| expected:", expected)
+			}
+			// принимается каждое второе изменение
+			if i%2 == 0 {
+				solution = change.apply(solution)
+				energies.accept(energy, state)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package packing
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ошибки во входных данных задачи
+var (
+	// ErrInvalidCapacity - вместимость контейнеров не положительна
+	ErrInvalidCapacity = errors.New("вместимость контейнеров должна быть положительной")
+	// ErrInvalidWeight - вес предмета не положителен
+	ErrInvalidWeight = errors.New("вес предмета должен быть положительным")
+	// ErrOversizedItem - предмет не помещается ни в один контейнер
+	ErrOversizedItem = errors.New("вес предмета превышает вместимость контейнера")
+)
+
+// InstanceError - Ошибка во входных данных задачи; сравнивается
+// с ErrInvalidCapacity, ErrInvalidWeight и ErrOversizedItem
+// с помощью errors.Is
+type InstanceError struct {
+	Err error
+	// Item - идентификатор предмета (-1 для ошибки вместимости)
+	Item     int
+	Weight   int
+	Capacity int
+}
+
+// Error - Описывает ошибку
+func (err *InstanceError) Error() string {
+	if err.Item < 0 {
+		return fmt.Sprintf("%v: %d", err.Err, err.Capacity)
+	}
+	return fmt.Sprintf("предмет %d: %v: %d (вместимость %d)", err.Item, err.Err, err.Weight, err.Capacity)
+}
+
+// Unwrap - Возвращает вид ошибки
+func (err *InstanceError) Unwrap() error {
+	return err.Err
+}
+
+// CheckInstance - Проверяет входные данные: вместимость положительна,
+// а вес каждого предмета положителен и не превышает вместимость.
+// Предмет идентифицируется индексом в weights
+func CheckInstance(weights []int, capacity int) error {
+	return CheckItems(NewItems(weights), capacity)
+}
+
+// CheckItems - Проверяет входные данные, заданные предметами;
+// задача без предметов корректна при любой вместимости.
+// Предмет идентифицируется своим ID
+func CheckItems(items []Item, capacity int) error {
+	if len(items) > 0 && capacity <= 0 {
+		return &InstanceError{Err: ErrInvalidCapacity, Item: -1, Capacity: capacity}
+	}
+	for _, item := range items {
+		if item.Weight <= 0 {
+			return &InstanceError{Err: ErrInvalidWeight, Item: item.ID, Weight: item.Weight, Capacity: capacity}
+		}
+		if item.Weight > capacity {
+			return &InstanceError{Err: ErrOversizedItem, Item: item.ID, Weight: item.Weight, Capacity: capacity}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package packing
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestCheckInstance(t *testing.T) {
+	samples := []struct {
+		weights []int
+		capacity int
+		err error
+		item int
+	}{
+		{ []int{ 1, 10 }, 10, nil, 0 },
+		{ nil, 0, nil, 0 },
+		{ []int{ 1 }, 0, ErrInvalidCapacity, -1 },
+		{ []int{ 1, 0 }, 10, ErrInvalidWeight, 1 },
+		{ []int{ 1, -3 }, 10, ErrInvalidWeight, 1 },
+		{ []int{ 4, 5, 11 }, 10, ErrOversizedItem, 2 },
+	}
+
+	for _, sample := range samples {
+		err := CheckInstance(sample.weights, sample.capacity)
+		if sample.err == nil {
+			if err != nil {
+				t.Error("weights:", sample.weights, "| unexpected error:", err)
+			}
+			continue
+		}
+		var instanceError *InstanceError
+		if !errors.Is(err, sample.err) || !errors.As(err, &instanceError) || instanceError.Item != sample.item {
+			t.Error("weights:", sample.weights, "| result:", err, "| expected:", sample.err, sample.item)
+		}
+	}
+}
+
+func TestCheckItems(t *testing.T) {
+	// в ошибке указывается идентификатор предмета, а не его индекс
+	items := []Item{ Item{ ID: 5, Weight: 4 }, Item{ ID: 71, Weight: 40 } }
+	var instanceError *InstanceError
+	if err := CheckItems(items, 10); !errors.As(err, &instanceError) || instanceError.Item != 71 {
+		t.Error("result:", err, "| expected item:", 71)
+	}
+}
+
+func TestAlgorithmsRejectInvalidInstances(t *testing.T) {
+	weights := []int{ 3, 12, 4 }
+	algorithms := map[string]func([]int, int) (Solution, error){
+		"BestFit":            BestFit,
+		"FastBestFit":        FastBestFit,
+		"NextFit":            NextFit,
+		"FirstFit":           FirstFit,
+		"WorstFit":           WorstFit,
+		"AlmostWorstFit":     AlmostWorstFit,
+		"FirstFitDecreasing": FirstFitDecreasing,
+		"BestFitDecreasing":  BestFitDecreasing,
+		"Exact": func(weights []int, capacity int) (Solution, error) {
+			result, err := Exact(weights, capacity, ExactOptions{})
+			return result.Solution, err
+		},
+		"SimulatedAnnealing": func(weights []int, capacity int) (Solution, error) {
+			result, err := SimulatedAnnealing(weights, capacity, DefaultAnnealOptions())
+			return result.Solution, err
+		},
+	}
+	for name, algorithm := range algorithms {
+		if _, err := algorithm(weights, 10); !errors.Is(err, ErrOversizedItem) {
+			t.Error(name, "error:", err, "| expected:", ErrOversizedItem)
+		}
+	}
+
+	// начальное решение отжига проверяется по входным данным
+	options := DefaultAnnealOptions()
+	options.InitialSolution = []Container{ fromWeights(3, 4) }
+	_, err := SimulatedAnnealing([]int{ 3, 4, 5 }, 10, options)
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		t.Error("error:", err, "| expected: *ValidationError")
+	}
+}
+
+func TestNoMoveAvailable(t *testing.T) {
+	// в единственном контейнере и в полностью заполненных контейнерах
+	// допустимых изменений нет: операторы сообщают об этом, а не
+	// перебирают варианты бесконечно
+	samples := []struct {
+		containers []Container
+		moves []Move
+	}{
+		{
+			[]Container{ fromWeights(3, 4) },
+			[]Move{ ShiftMove{}, SwapMove{}, Swap21Move{}, Swap22Move{}, EjectMove{}, ChainMove{} },
+		}, {
+			[]Container{ fromWeights(5, 5), fromWeights(4, 6) },
+			[]Move{ ShiftMove{}, SwapMove{}, ChainMove{} },
+		},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, sample := range samples {
+		for _, move := range sample.moves {
+			if change, ok := move.Propose(sample.containers, 10, rng); ok {
+				t.Error(move, "containers:", sample.containers, "| change:", change)
+			}
+		}
+	}
+
+	result, err := SimulatedAnnealing([]int{ 3, 4 }, 10, DefaultAnnealOptions())
+	if err != nil || result.NumBins() != 1 || result.Rejected != result.Iterations {
+		t.Error("result:", result.Containers, "| error:", err)
+	}
+}
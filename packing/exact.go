@@ -0,0 +1,325 @@
+package packing
+
+import (
+	"sort"
+	"time"
+)
+
+// ExactOptions - Ограничения на работу точного алгоритма.
+// Нулевые значения означают отсутствие ограничения
+type ExactOptions struct {
+	// NodeLimit - максимальное число узлов дерева поиска
+	NodeLimit int
+	// TimeLimit - максимальное время работы
+	TimeLimit time.Duration
+}
+
+// ExactResult - Результат работы точного алгоритма
+type ExactResult struct {
+	// Solution - лучшее найденное решение
+	Solution
+	// LowerBound - доказанная нижняя оценка числа контейнеров
+	LowerBound int
+	// Optimal - доказана ли оптимальность решения
+	Optimal bool
+	// Nodes - число просмотренных узлов дерева поиска
+	Nodes int
+}
+
+// Gap - Разница между числом контейнеров найденного решения и
+// доказанной нижней оценкой (0 для оптимального решения)
+func (result ExactResult) Gap() int {
+	return result.NumBins() - result.LowerBound
+}
+
+// freeList - Множество свободных (ещё не упакованных) предметов,
+// упорядоченных по невозрастанию веса, с быстрым поиском
+// ближайшего свободного предмета справа и слева
+type freeList struct {
+	next []int
+	prev []int
+}
+
+func newFreeList(n int) *freeList {
+	list := &freeList{next: make([]int, n+2), prev: make([]int, n+2)}
+	for i := range list.next {
+		list.next[i] = i
+		list.prev[i] = i
+	}
+	return list
+}
+
+// right - Индекс первого свободного предмета, начиная с i (n, если таких нет)
+func (list *freeList) right(i int) int {
+	for list.next[i] != i {
+		list.next[i] = list.next[list.next[i]]
+		i = list.next[i]
+	}
+	return i
+}
+
+// left - Индекс последнего свободного предмета, не превышающий i
+// (-1, если таких нет)
+func (list *freeList) left(i int) int {
+	// индексы prev смещены на 1, чтобы -1 был корректным значением
+	i++
+	for list.prev[i] != i {
+		list.prev[i] = list.prev[list.prev[i]]
+		i = list.prev[i]
+	}
+	return i - 1
+}
+
+// remove - Помечает предмет с индексом i упакованным
+func (list *freeList) remove(i int) {
+	list.next[i] = i + 1
+	list.prev[i+1] = i
+}
+
+/*
+	reduce
+	Процедура сокращения Мартелло-Тота (MTRP): фиксирует контейнеры,
+	которые заведомо входят в некоторое оптимальное решение
+	входные данные:
+		sorted - предметы по невозрастанию веса
+		capacity - вместимость контейнеров
+	выходные данные:
+		зафиксированные контейнеры и оставшиеся предметы
+		(по невозрастанию веса)
+*/
+func reduce(sorted []Item, capacity int) ([]Container, []Item) {
+	n := len(sorted)
+	free := newFreeList(n)
+	var fixed []Container
+
+	// первый индекс предмета с весом не больше x
+	firstFitting := func(x int) int {
+		return sort.Search(n, func(i int) bool { return sorted[i].Weight <= x })
+	}
+
+	for j := free.right(0); j < n; j = free.right(j + 1) {
+		residual := capacity - sorted[j].Weight
+
+		// наибольший свободный предмет k != j, влезающий вместе с j
+		k := free.right(firstFitting(residual))
+		if k == j {
+			k = free.right(j + 1)
+		}
+
+		if k >= n {
+			// с предметом j не влезает ни один другой предмет
+			free.remove(j)
+			container := New()
+			container.append(sorted[j])
+			fixed = append(fixed, container)
+			continue
+		}
+
+		// два наименьших свободных предмета, отличных от j
+		a := free.left(n - 1)
+		if a == j {
+			a = free.left(j - 1)
+		}
+		b := -1
+		if a >= 0 {
+			b = free.left(a - 1)
+			if b == j {
+				b = free.left(j - 1)
+			}
+		}
+
+		// пара {j, k} доминирует над любым допустимым набором с j,
+		// если k заполняет контейнер полностью или никакие два
+		// других предмета не влезают вместе с j
+		if sorted[k].Weight == residual || b < 0 || sorted[a].Weight+sorted[b].Weight > residual {
+			free.remove(j)
+			free.remove(k)
+			container := New()
+			container.append(sorted[j])
+			container.append(sorted[k])
+			fixed = append(fixed, container)
+		}
+	}
+
+	var rest []Item
+	for i := free.right(0); i < n; i = free.right(i + 1) {
+		rest = append(rest, sorted[i])
+	}
+	return fixed, rest
+}
+
+// branchAndBound - Состояние поиска с возвратом по дереву
+// назначений предметов в контейнеры
+type branchAndBound struct {
+	weights  []int
+	capacity int
+	options  ExactOptions
+	deadline time.Time
+
+	// суффиксные суммы весов
+	suffix []int
+	// текущее назначение: индекс контейнера для каждого предмета
+	assignment []int
+	loads      []int
+
+	// лучшее найденное решение
+	best           int
+	bestAssignment []int
+	lowerBound     int
+
+	nodes   int
+	stopped bool
+}
+
+// exhausted - Проверяет, исчерпан ли бюджет узлов или времени
+func (search *branchAndBound) exhausted() bool {
+	if search.options.NodeLimit > 0 && search.nodes >= search.options.NodeLimit {
+		return true
+	}
+	// время проверяется не на каждом узле
+	if search.options.TimeLimit > 0 && search.nodes%1024 == 0 && time.Now().After(search.deadline) {
+		return true
+	}
+	return false
+}
+
+// solve - Назначает предмет i и все последующие
+func (search *branchAndBound) solve(i int) {
+	if search.stopped || search.best == search.lowerBound {
+		return
+	}
+	search.nodes++
+	if search.exhausted() {
+		search.stopped = true
+		return
+	}
+
+	m := len(search.loads)
+	if i == len(search.weights) {
+		if m < search.best {
+			search.best = m
+			copy(search.bestAssignment, search.assignment)
+		}
+		return
+	}
+
+	// оставшиеся предметы должны поместиться в свободное место
+	// открытых контейнеров, иначе понадобятся новые контейнеры
+	free := 0
+	for _, load := range search.loads {
+		free += search.capacity - load
+	}
+	bound := m
+	if search.suffix[i] > free {
+		bound += ceilDiv(search.suffix[i]-free, search.capacity)
+	}
+	if bound >= search.best {
+		return
+	}
+
+	weight := search.weights[i]
+	for c := 0; c < m; c++ {
+		if search.loads[c]+weight > search.capacity {
+			continue
+		}
+		// контейнеры с одинаковой загрузкой взаимозаменяемы
+		duplicate := false
+		for d := 0; d < c; d++ {
+			if search.loads[d] == search.loads[c] {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		search.loads[c] += weight
+		search.assignment[i] = c
+		search.solve(i + 1)
+		search.loads[c] -= weight
+	}
+
+	// открываем новый контейнер, только если это может улучшить решение
+	if m+1 < search.best {
+		search.loads = append(search.loads, weight)
+		search.assignment[i] = m
+		search.solve(i + 1)
+		search.loads = search.loads[:m]
+	}
+}
+
+/*
+	Exact
+	Точный алгоритм ветвей и границ в духе MTP (Мартелло-Тот):
+	сокращение задачи, начальное решение BFD, отсечение по нижним оценкам
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+		options - ограничения на число узлов и время работы
+	выходные данные:
+		оптимальное решение, либо лучшее найденное решение и
+		доказанная нижняя оценка, если бюджет был исчерпан;
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func Exact(weights []int, capacity int, options ExactOptions) (ExactResult, error) {
+	return ExactItems(NewItems(weights), capacity, options)
+}
+
+// ExactItems - Точный алгоритм для предметов с идентификаторами
+func ExactItems(items []Item, capacity int, options ExactOptions) (ExactResult, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return ExactResult{}, err
+	}
+
+	fixed, rest := reduce(sortItemsDecreasing(items), capacity)
+	if len(rest) == 0 {
+		return ExactResult{
+			Solution:   Solution{Containers: fixed, Capacity: capacity},
+			LowerBound: len(fixed),
+			Optimal:    true,
+		}, nil
+	}
+	weights := itemWeights(rest)
+
+	// начальное решение для оставшихся предметов
+	// входные данные уже проверены, поэтому ошибки быть не может
+	bfd, _ := BestFitDecreasingItems(rest, capacity)
+	initial := bfd.Containers
+
+	search := &branchAndBound{
+		weights:        weights,
+		capacity:       capacity,
+		options:        options,
+		deadline:       time.Now().Add(options.TimeLimit),
+		suffix:         make([]int, len(rest)+1),
+		assignment:     make([]int, len(rest)),
+		best:           len(initial),
+		bestAssignment: make([]int, len(rest)),
+		lowerBound:     LowerBound(weights, capacity).Best(),
+	}
+	for i := len(rest) - 1; i >= 0; i-- {
+		search.suffix[i] = search.suffix[i+1] + weights[i]
+	}
+	search.solve(0)
+
+	result := ExactResult{Nodes: search.nodes}
+
+	// если поиск ничего не улучшил, то лучшим остаётся начальное решение
+	containers := initial
+	if search.best < len(initial) {
+		containers = make([]Container, search.best)
+		for i, c := range search.bestAssignment {
+			containers[c].append(rest[i])
+		}
+	}
+	result.Solution = Solution{Containers: append(fixed, containers...), Capacity: capacity}
+
+	if search.stopped {
+		result.LowerBound = len(fixed) + search.lowerBound
+	} else {
+		result.LowerBound = len(result.Containers)
+	}
+	result.Optimal = result.LowerBound == len(result.Containers)
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package packing
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce - Находит оптимальное число контейнеров полным перебором
+func bruteForce(weights []int, capacity int) int {
+	best := len(weights)
+	loads := []int{}
+	var assign func(i int)
+	assign = func(i int) {
+		if len(loads) >= best {
+			return
+		}
+		if i == len(weights) {
+			best = len(loads)
+			return
+		}
+		for c := range loads {
+			if loads[c]+weights[i] <= capacity {
+				loads[c] += weights[i]
+				assign(i + 1)
+				loads[c] -= weights[i]
+			}
+		}
+		loads = append(loads, weights[i])
+		assign(i + 1)
+		loads = loads[:len(loads)-1]
+	}
+	assign(0)
+	return best
+}
+
+func TestExact(t *testing.T) {
+	samples := []struct {
+		weights []int
+		capacity int
+		count int
+	}{
+		{
+			[]int{},
+			10,
+			0,
+		}, {
+			[]int{ 6, 6, 6 },
+			10,
+			3,
+		}, {
+			// BFD использует 3 контейнера, оптимум - 2
+			[]int{ 3, 4, 3, 3, 4, 3 },
+			10,
+			2,
+		}, {
+			[]int{ 49, 41, 34, 33, 29, 26, 26, 22, 20, 19 },
+			100,
+			3,
+		},
+	}
+
+	for _, sample := range samples {
+		result, err := Exact(sample.weights, sample.capacity, ExactOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Containers) != sample.count || !result.Optimal || result.Gap() != 0 {
+			t.Error("result:", result, "| expected count:", sample.count)
+		}
+		if Validate(sample.weights, sample.capacity, result.Containers) != nil {
+			t.Error("invalid packing:", result.Containers)
+		}
+	}
+}
+
+func TestExactRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iteration := 0; iteration < 200; iteration++ {
+		capacity := 10 + rng.Intn(20)
+		weights := make([]int, 1+rng.Intn(9))
+		for i := range weights {
+			weights[i] = 1 + rng.Intn(capacity)
+		}
+
+		expected := bruteForce(weights, capacity)
+		result, err := Exact(weights, capacity, ExactOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Containers) != expected || !result.Optimal {
+			t.Error("weights:", weights, "| capacity:", capacity,
+				"| result:", len(result.Containers), "| expected:", expected)
+		}
+		if Validate(weights, capacity, result.Containers) != nil {
+			t.Error("invalid packing:", result.Containers)
+		}
+	}
+}
+
+func TestExactNodeLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	weights := make([]int, 60)
+	for i := range weights {
+		weights[i] = 20 + rng.Intn(80)
+	}
+
+	result, err := Exact(weights, 150, ExactOptions{NodeLimit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Nodes > 10 {
+		t.Error("nodes:", result.Nodes, "| limit:", 10)
+	}
+	if result.Gap() < 0 || result.Optimal != (result.Gap() == 0) {
+		t.Error("lower bound:", result.LowerBound, "| containers:", len(result.Containers))
+	}
+	if Validate(weights, 150, result.Containers) != nil {
+		t.Error("invalid packing:", result.Containers)
+	}
+}
@@ -0,0 +1,190 @@
+package packing
+
+// residualKey - Ключ контейнера в дереве: оставшееся место и индекс
+// контейнера (при равном месте меньше тот, что открыт раньше)
+type residualKey struct {
+	residual int
+	index    int
+}
+
+// less - Сравнивает ключи
+func (key residualKey) less(another residualKey) bool {
+	if key.residual != another.residual {
+		return key.residual < another.residual
+	}
+	return key.index < another.index
+}
+
+// treapNode - Узел декартова дерева
+type treapNode struct {
+	key         residualKey
+	priority    uint64
+	left, right int
+}
+
+// residualTree - Декартово дерево (treap) открытых контейнеров,
+// упорядоченных по оставшемуся месту. Узлы хранятся в срезе,
+// индекс узла совпадает с индексом контейнера; -1 - пустое поддерево
+type residualTree struct {
+	nodes []treapNode
+	root  int
+	// seed - состояние генератора приоритетов (xorshift), чтобы
+	// результат не зависел от глобального генератора
+	seed uint64
+}
+
+// newResidualTree - Создаёт пустое дерево
+func newResidualTree(size int) *residualTree {
+	return &residualTree{
+		nodes: make([]treapNode, 0, size),
+		root:  -1,
+		seed:  0x9E3779B97F4A7C15,
+	}
+}
+
+// nextPriority - Возвращает очередной псевдослучайный приоритет
+func (tree *residualTree) nextPriority() uint64 {
+	tree.seed ^= tree.seed << 13
+	tree.seed ^= tree.seed >> 7
+	tree.seed ^= tree.seed << 17
+	return tree.seed
+}
+
+// split - Разделяет поддерево на узлы с ключами меньше key и остальные
+func (tree *residualTree) split(t int, key residualKey) (int, int) {
+	if t == -1 {
+		return -1, -1
+	}
+	node := &tree.nodes[t]
+	if node.key.less(key) {
+		left, right := tree.split(node.right, key)
+		tree.nodes[t].right = left
+		return t, right
+	}
+	left, right := tree.split(node.left, key)
+	tree.nodes[t].left = right
+	return left, t
+}
+
+// merge - Объединяет поддеревья, если все ключи left меньше ключей right
+func (tree *residualTree) merge(left, right int) int {
+	if left == -1 {
+		return right
+	}
+	if right == -1 {
+		return left
+	}
+	if tree.nodes[left].priority > tree.nodes[right].priority {
+		tree.nodes[left].right = tree.merge(tree.nodes[left].right, right)
+		return left
+	}
+	tree.nodes[right].left = tree.merge(left, tree.nodes[right].left)
+	return right
+}
+
+// insert - Добавляет узел t с его текущим ключом
+func (tree *residualTree) insert(t int) {
+	tree.nodes[t].left, tree.nodes[t].right = -1, -1
+	left, right := tree.split(tree.root, tree.nodes[t].key)
+	tree.root = tree.merge(tree.merge(left, t), right)
+}
+
+// erase - Удаляет узел с ключом key из поддерева t
+func (tree *residualTree) erase(t int, key residualKey) int {
+	node := tree.nodes[t]
+	if node.key == key {
+		return tree.merge(node.left, node.right)
+	}
+	if key.less(node.key) {
+		tree.nodes[t].left = tree.erase(node.left, key)
+	} else {
+		tree.nodes[t].right = tree.erase(node.right, key)
+	}
+	return t
+}
+
+// add - Добавляет в дерево новый контейнер с оставшимся местом residual
+func (tree *residualTree) add(residual int) {
+	index := len(tree.nodes)
+	tree.nodes = append(tree.nodes, treapNode{
+		key:      residualKey{residual: residual, index: index},
+		priority: tree.nextPriority(),
+	})
+	tree.insert(index)
+}
+
+// update - Изменяет оставшееся место в контейнере index
+func (tree *residualTree) update(index, residual int) {
+	tree.root = tree.erase(tree.root, tree.nodes[index].key)
+	tree.nodes[index].key.residual = residual
+	tree.insert(index)
+}
+
+// fit - Возвращает индекс контейнера с наименьшим оставшимся местом,
+// не меньшим weight (-1, если такого контейнера нет)
+func (tree *residualTree) fit(weight int) int {
+	target := residualKey{residual: weight, index: -1}
+	result := -1
+	for t := tree.root; t != -1; {
+		node := tree.nodes[t]
+		if node.key.less(target) {
+			t = node.right
+		} else {
+			result = node.key.index
+			t = node.left
+		}
+	}
+	return result
+}
+
+/*
+	FastBestFit
+	Алгоритм наилучший подходящий (BF) за O(n log n): контейнеры
+	хранятся в декартовом дереве, упорядоченном по оставшемуся месту,
+	поэтому наиболее подходящий контейнер находится без перебора всех
+	открытых. Упаковка совпадает с упаковкой BestFit: при равном
+	оставшемся месте выбирается контейнер с меньшим индексом
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		решение (заполненные предметами контейнеры) или
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func FastBestFit(weights []int, capacity int) (Solution, error) {
+	return FastBestFitItems(NewItems(weights), capacity)
+}
+
+// FastBestFitItems - Алгоритм FastBestFit для предметов с идентификаторами
+func FastBestFitItems(items []Item, capacity int) (Solution, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return Solution{}, err
+	}
+
+	if len(items) == 0 {
+		return Solution{Capacity: capacity}, nil
+	}
+
+	containers := []Container{New()}
+
+	n := len(items)
+
+	tree := newResidualTree(n)
+	// помещаем 1-й предмет в 1-й контейнер
+	containers[0].append(items[0])
+	tree.add(containers[0].GetPadding(capacity))
+
+	for k := 1; k < n; k++ {
+		i := tree.fit(items[k].Weight)
+		if i == -1 {
+			containers = append(containers, New())
+			i = len(containers) - 1
+			containers[i].append(items[k])
+			tree.add(containers[i].GetPadding(capacity))
+			continue
+		}
+		containers[i].append(items[k])
+		tree.update(i, containers[i].GetPadding(capacity))
+	}
+	return Solution{Containers: containers, Capacity: capacity}, nil
+}
@@ -0,0 +1,73 @@
+package packing
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// sameIDs - Проверяет, что в соответствующих контейнерах
+// лежат одни и те же предметы в одном и том же порядке
+func sameIDs(first, second []Container) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for i := range first {
+		a, b := first[i].IDs(), second[i].IDs()
+		if len(a) != len(b) {
+			return false
+		}
+		for j := range a {
+			if a[j] != b[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestFastBestFit(t *testing.T) {
+	// упаковка совпадает с упаковкой BestFit
+	rng := rand.New(rand.NewSource(6))
+	samples := []struct {
+		n int
+		capacity int
+		maxWeight int
+	}{
+		{ 0, 10, 10 },
+		{ 1, 10, 10 },
+		{ 50, 10, 10 },
+		// много одинаковых остатков
+		{ 500, 20, 5 },
+		{ 2000, 150, 100 },
+		{ 2000, 1000, 700 },
+	}
+
+	for _, sample := range samples {
+		weights := make([]int, sample.n)
+		for i := range weights {
+			weights[i] = 1 + rng.Intn(sample.maxWeight)
+		}
+		expected := bestFit(weights, sample.capacity)
+		solution, err := FastBestFit(weights, sample.capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := solution.Containers
+		if !sameIDs(result, expected) {
+			t.Error("n:", sample.n, "capacity:", sample.capacity, "| result:", result, "| expected:", expected)
+		}
+		if !consistentLoads(result) {
+			t.Error("n:", sample.n, "| inconsistent loads:", result)
+		}
+	}
+
+	if result, err := FastBestFit(exampleWeights, 150); err != nil || !sameIDs(result.Containers, bestFit(exampleWeights, 150)) {
+		t.Error("result:", result, err)
+	}
+
+	// некорректные входные данные отвергаются так же, как в BestFit
+	if _, err := FastBestFit([]int{ 30, 80 }, 50); !errors.Is(err, ErrOversizedItem) {
+		t.Error("error:", err, "| expected:", ErrOversizedItem)
+	}
+}
@@ -0,0 +1,249 @@
+package packing
+
+import (
+	"sort"
+)
+
+/*
+	NextFit
+	Алгоритм следующий подходящий (NF)
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		решение (заполненные предметами контейнеры) или
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func NextFit(weights []int, capacity int) (Solution, error) {
+	return NextFitItems(NewItems(weights), capacity)
+}
+
+// NextFitItems - Алгоритм NextFit для предметов с идентификаторами
+func NextFitItems(items []Item, capacity int) (Solution, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return Solution{}, err
+	}
+
+	if len(items) == 0 {
+		return Solution{Capacity: capacity}, nil
+	}
+
+	containers := []Container{New()}
+
+	// индекс текущего (единственного открытого) контейнера
+	current := 0
+	for k, item := range items {
+		// если предмет не влезает в текущий контейнер,
+		// то закрываем его и открываем новый
+		if k > 0 && containers[current].GetPadding(capacity) < item.Weight {
+			containers = append(containers, New())
+			current++
+		}
+		containers[current].append(item)
+	}
+	return Solution{Containers: containers, Capacity: capacity}, nil
+}
+
+/*
+	FirstFit
+	Алгоритм первый подходящий (FF)
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		решение (заполненные предметами контейнеры) или
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func FirstFit(weights []int, capacity int) (Solution, error) {
+	return FirstFitItems(NewItems(weights), capacity)
+}
+
+// FirstFitItems - Алгоритм FirstFit для предметов с идентификаторами
+func FirstFitItems(items []Item, capacity int) (Solution, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return Solution{}, err
+	}
+
+	if len(items) == 0 {
+		return Solution{Capacity: capacity}, nil
+	}
+
+	containers := []Container{New()}
+
+	n := len(items)
+	containers[0].append(items[0])
+
+	for k := 1; k < n; k++ {
+		// ищем первый контейнер, в который влезает текущий предмет
+		firstI := -1
+		m := len(containers)
+		for i := 0; i < m; i++ {
+			if containers[i].GetPadding(capacity) >= items[k].Weight {
+				firstI = i
+				break
+			}
+		}
+		if firstI != -1 {
+			containers[firstI].append(items[k])
+		} else {
+			containers = append(containers, New())
+			containers[m].append(items[k])
+		}
+	}
+	return Solution{Containers: containers, Capacity: capacity}, nil
+}
+
+/*
+	WorstFit
+	Алгоритм наихудший подходящий (WF)
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		решение (заполненные предметами контейнеры) или
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func WorstFit(weights []int, capacity int) (Solution, error) {
+	return WorstFitItems(NewItems(weights), capacity)
+}
+
+// WorstFitItems - Алгоритм WorstFit для предметов с идентификаторами
+func WorstFitItems(items []Item, capacity int) (Solution, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return Solution{}, err
+	}
+
+	if len(items) == 0 {
+		return Solution{Capacity: capacity}, nil
+	}
+
+	containers := []Container{New()}
+
+	n := len(items)
+	containers[0].append(items[0])
+
+	for k := 1; k < n; k++ {
+		// ищем контейнер, в котором после добавления
+		// текущего предмета останется больше всего места
+		maxDelta, maxI := -1, -1
+		m := len(containers)
+		for i := 0; i < m; i++ {
+			delta := containers[i].GetPadding(capacity) - items[k].Weight
+			if delta >= 0 && delta > maxDelta {
+				maxDelta = delta
+				maxI = i
+			}
+		}
+		if maxI != -1 {
+			containers[maxI].append(items[k])
+		} else {
+			containers = append(containers, New())
+			containers[m].append(items[k])
+		}
+	}
+	return Solution{Containers: containers, Capacity: capacity}, nil
+}
+
+/*
+	AlmostWorstFit
+	Алгоритм почти наихудший подходящий (AWF): предмет помещается
+	во второй по величине оставшегося места контейнер, а если подходит
+	только один контейнер - то в него
+	входные данные:
+		weights - веса предметов
+		capacity - вместимость контейнеров
+	выходные данные:
+		решение (заполненные предметами контейнеры) или
+		ошибка *InstanceError, если входные данные некорректны
+*/
+func AlmostWorstFit(weights []int, capacity int) (Solution, error) {
+	return AlmostWorstFitItems(NewItems(weights), capacity)
+}
+
+// AlmostWorstFitItems - Алгоритм AlmostWorstFit для предметов с идентификаторами
+func AlmostWorstFitItems(items []Item, capacity int) (Solution, error) {
+	if err := CheckItems(items, capacity); err != nil {
+		return Solution{}, err
+	}
+
+	if len(items) == 0 {
+		return Solution{Capacity: capacity}, nil
+	}
+
+	containers := []Container{New()}
+
+	n := len(items)
+	containers[0].append(items[0])
+
+	for k := 1; k < n; k++ {
+		// наибольший и второй по величине остатки места
+		// среди контейнеров, куда влезает текущий предмет
+		maxDelta, maxI := -1, -1
+		secondDelta, secondI := -1, -1
+		m := len(containers)
+		for i := 0; i < m; i++ {
+			delta := containers[i].GetPadding(capacity) - items[k].Weight
+			if delta < 0 {
+				continue
+			}
+			if delta > maxDelta {
+				secondDelta, secondI = maxDelta, maxI
+				maxDelta, maxI = delta, i
+			} else if delta > secondDelta {
+				secondDelta, secondI = delta, i
+			}
+		}
+		if secondI != -1 {
+			containers[secondI].append(items[k])
+		} else if maxI != -1 {
+			containers[maxI].append(items[k])
+		} else {
+			containers = append(containers, New())
+			containers[m].append(items[k])
+		}
+	}
+	return Solution{Containers: containers, Capacity: capacity}, nil
+}
+
+// sortItemsDecreasing - Возвращает копию предметов, упорядоченную по
+// невозрастанию веса (предметы равного веса сохраняют порядок)
+func sortItemsDecreasing(items []Item) []Item {
+	// упорядочиваются индексы, а не сами предметы: перестановка
+	// предметов с сопутствующими данными при устойчивой сортировке
+	// на больших задачах занимает больше времени, чем упаковка
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		return items[i].Weight > items[j].Weight || (items[i].Weight == items[j].Weight && i < j)
+	})
+	sorted := make([]Item, len(items))
+	for k, i := range order {
+		sorted[k] = items[i]
+	}
+	return sorted
+}
+
+// FirstFitDecreasing - Алгоритм первый подходящий с упорядочиванием
+// предметов по невозрастанию веса (FFD)
+func FirstFitDecreasing(weights []int, capacity int) (Solution, error) {
+	return FirstFitDecreasingItems(NewItems(weights), capacity)
+}
+
+// FirstFitDecreasingItems - Алгоритм FFD для предметов с идентификаторами
+func FirstFitDecreasingItems(items []Item, capacity int) (Solution, error) {
+	return FirstFitItems(sortItemsDecreasing(items), capacity)
+}
+
+// BestFitDecreasing - Алгоритм наилучший подходящий с упорядочиванием
+// предметов по невозрастанию веса (BFD)
+func BestFitDecreasing(weights []int, capacity int) (Solution, error) {
+	return BestFitDecreasingItems(NewItems(weights), capacity)
+}
+
+// BestFitDecreasingItems - Алгоритм BFD для предметов с идентификаторами
+func BestFitDecreasingItems(items []Item, capacity int) (Solution, error) {
+	return BestFitItems(sortItemsDecreasing(items), capacity)
+}
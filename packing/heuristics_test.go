@@ -0,0 +1,101 @@
+package packing
+
+import (
+	"testing"
+)
+
+func TestHeuristics(t *testing.T) {
+	samples := []struct {
+		name string
+		algorithm func([]int, int) (Solution, error)
+		weights []int
+		capacity int
+		containers []Container
+	}{
+		{
+			"NextFit",
+			NextFit,
+			[]int{ 4, 2, 5, 1, 3 },
+			6,
+			[]Container{
+				fromWeights(4, 2),
+				fromWeights(5, 1),
+				fromWeights(3),
+			},
+		}, {
+			"FirstFit",
+			FirstFit,
+			[]int{ 4, 3, 2, 2 },
+			6,
+			[]Container{
+				fromWeights(4, 2),
+				fromWeights(3, 2),
+			},
+		}, {
+			"WorstFit",
+			WorstFit,
+			[]int{ 2, 5, 1 },
+			6,
+			[]Container{
+				fromWeights(2, 1),
+				fromWeights(5),
+			},
+		}, {
+			"AlmostWorstFit",
+			AlmostWorstFit,
+			[]int{ 5, 4, 1, 1 },
+			6,
+			[]Container{
+				fromWeights(5, 1),
+				fromWeights(4, 1),
+			},
+		}, {
+			"FirstFitDecreasing",
+			FirstFitDecreasing,
+			[]int{ 1, 2, 3, 4, 5 },
+			6,
+			[]Container{
+				fromWeights(5, 1),
+				fromWeights(4, 2),
+				fromWeights(3),
+			},
+		}, {
+			"BestFitDecreasing",
+			BestFitDecreasing,
+			[]int{ 2, 3, 4, 2, 5 },
+			7,
+			[]Container{
+				fromWeights(5, 2),
+				fromWeights(4, 3),
+				fromWeights(2),
+			},
+		}, {
+			"FirstFitDecreasing",
+			FirstFitDecreasing,
+			nil,
+			10,
+			nil,
+		},
+	}
+
+	for _, sample := range samples {
+		expected := sample.containers
+		solution, err := sample.algorithm(sample.weights, sample.capacity)
+		if err != nil {
+			t.Fatal(sample.name, err)
+		}
+		result := solution.Containers
+		if !areEqual(result, expected) {
+			t.Error(sample.name, "result:", result, "| expected:", expected)
+		}
+	}
+}
+
+func TestDecreasingKeepsInput(t *testing.T) {
+	weights := []int{ 1, 2, 3 }
+	FirstFitDecreasing(weights, 3)
+	BestFitDecreasing(weights, 3)
+	if weights[0] != 1 || weights[1] != 2 || weights[2] != 3 {
+		t.Error("input weights were modified:", weights)
+	}
+}
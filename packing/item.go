@@ -0,0 +1,28 @@
+package packing
+
+// Item - Предмет: идентификатор, вес и произвольные
+// сопутствующие данные, которые алгоритмы не используют
+type Item struct {
+	ID      int
+	Weight  int
+	Payload interface{}
+}
+
+// NewItems - Создаёт предметы с заданными весами;
+// идентификатор предмета - его индекс в weights
+func NewItems(weights []int) []Item {
+	items := make([]Item, len(weights))
+	for i, weight := range weights {
+		items[i] = Item{ID: i, Weight: weight}
+	}
+	return items
+}
+
+// itemWeights - Возвращает веса предметов
+func itemWeights(items []Item) []int {
+	weights := make([]int, len(items))
+	for i, item := range items {
+		weights[i] = item.Weight
+	}
+	return weights
+}
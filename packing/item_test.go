@@ -0,0 +1,84 @@
+package packing
+
+import (
+	"testing"
+)
+
+func TestItems(t *testing.T) {
+	// предметы одинакового веса различаются идентификаторами
+	items := []Item{
+		Item{ ID: 10, Weight: 42, Payload: "a" },
+		Item{ ID: 11, Weight: 42, Payload: "b" },
+		Item{ ID: 12, Weight: 58, Payload: "c" },
+		Item{ ID: 13, Weight: 30 },
+		Item{ ID: 14, Weight: 70, Payload: 7 },
+	}
+	algorithms := map[string]func([]Item, int) (Solution, error){
+		"BestFit":            BestFitItems,
+		"FastBestFit":        FastBestFitItems,
+		"NextFit":            NextFitItems,
+		"FirstFit":           FirstFitItems,
+		"WorstFit":           WorstFitItems,
+		"AlmostWorstFit":     AlmostWorstFitItems,
+		"FirstFitDecreasing": FirstFitDecreasingItems,
+		"BestFitDecreasing":  BestFitDecreasingItems,
+		"Exact": func(items []Item, capacity int) (Solution, error) {
+			result, err := ExactItems(items, capacity, ExactOptions{})
+			return result.Solution, err
+		},
+		"SimulatedAnnealing": func(items []Item, capacity int) (Solution, error) {
+			options := DefaultAnnealOptions()
+			options.Moves = []WeightedMove{ WeightedMove{ Move: EjectMove{}, Weight: 1 } }
+			result, err := SimulatedAnnealingItems(items, capacity, options)
+			return result.Solution, err
+		},
+	}
+	for name, algorithm := range algorithms {
+		result, err := algorithm(items, 100)
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if err := ValidateItems(items, 100, result.Containers); err != nil {
+			t.Error(name, err)
+		}
+		// сопутствующие данные остаются при своих предметах
+		for _, container := range result.Containers {
+			for _, item := range container.Items() {
+				if item != items[item.ID-10] {
+					t.Error(name, "result:", item, "| expected:", items[item.ID-10])
+				}
+			}
+		}
+
+		// для пустой задачи решение не содержит контейнеров
+		if result, err := algorithm(nil, 100); err != nil || result.NumBins() != 0 {
+			t.Error(name, "empty instance:", result.Containers, err)
+		}
+	}
+
+	// оптимальная упаковка: 42 + 58, 42 + 30, 70
+	result, err := ExactItems(items, 100, ExactOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NumBins() != 3 || !result.Optimal {
+		t.Error("bins:", result.NumBins(), "| optimal:", result.Optimal)
+	}
+}
+
+func TestNewItems(t *testing.T) {
+	items := NewItems([]int{ 5, 3 })
+	if len(items) != 2 || items[0] != (Item{ ID: 0, Weight: 5 }) || items[1] != (Item{ ID: 1, Weight: 3 }) {
+		t.Error("result:", items)
+	}
+
+	// идентификаторы предметов соответствуют индексам во входных данных
+	result, err := FirstFitDecreasing([]int{ 2, 9, 4 }, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := result.Containers[0].IDs()
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Error("ids:", ids, "| expected:", []int{ 1 })
+	}
+}
@@ -0,0 +1,464 @@
+package packing
+
+import (
+	"math/rand"
+)
+
+// moveAttempts - Число попыток найти допустимое изменение
+// для операторов, выбирающих предметы случайно
+const moveAttempts = 32
+
+// Change - Предлагаемое изменение решения: новое содержимое
+// нескольких контейнеров. Контейнеры, ставшие пустыми, удаляются
+type Change struct {
+	// Indices - индексы изменяемых контейнеров (без повторений);
+	// индекс -1 означает новый контейнер
+	Indices []int
+	// Contents - новое содержимое соответствующих контейнеров
+	Contents []Container
+}
+
+// add - Добавляет в изменение новое содержимое контейнера
+func (change *Change) add(index int, content Container) {
+	change.Indices = append(change.Indices, index)
+	change.Contents = append(change.Contents, content)
+}
+
+// apply - Применяет изменение к решению на месте и возвращает
+// решение (срез может быть перераспределён при добавлении контейнеров)
+func (change Change) apply(containers []Container) []Container {
+	var emptied []int
+	for i, index := range change.Indices {
+		content := change.Contents[i]
+		if index == -1 {
+			if content.Len() > 0 {
+				containers = append(containers, content)
+			}
+			continue
+		}
+		containers[index] = content
+		if content.Len() == 0 {
+			emptied = append(emptied, index)
+		}
+	}
+
+	// пустые контейнеры удаляются по убыванию индекса: на место
+	// удаляемого ставится последний контейнер
+	sortDescending(emptied)
+	for _, index := range emptied {
+		last := len(containers) - 1
+		containers[index] = containers[last]
+		containers[last] = Container{}
+		containers = containers[:last]
+	}
+	return containers
+}
+
+// sortDescending - Упорядочивает небольшой срез индексов по убыванию
+func sortDescending(indices []int) {
+	for i := 1; i < len(indices); i++ {
+		for j := i; j > 0 && indices[j] > indices[j-1]; j-- {
+			indices[j], indices[j-1] = indices[j-1], indices[j]
+		}
+	}
+}
+
+// modified - Возвращает копию контейнера без предметов с индексами
+// removed и с добавленными предметами added
+func modified(container Container, removed []int, added ...Item) Container {
+	result := New()
+	for i, item := range container.items {
+		skip := false
+		for _, index := range removed {
+			if i == index {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			result.append(item)
+		}
+	}
+	for _, item := range added {
+		result.append(item)
+	}
+	return result
+}
+
+// Move - Оператор окрестности: предлагает изменение решения
+type Move interface {
+	// Propose - возвращает изменение, не изменяя текущее решение;
+	// false, если допустимого изменения найти не удалось
+	Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool)
+}
+
+// MoveFunc - Позволяет использовать обычную функцию как Move
+type MoveFunc func(containers []Container, capacity int, rng *rand.Rand) (Change, bool)
+
+// Propose - Вызывает саму функцию
+func (f MoveFunc) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	return f(containers, capacity, rng)
+}
+
+// WeightedMove - Оператор окрестности и его вес: вероятность выбора
+// оператора пропорциональна весу
+type WeightedMove struct {
+	Move   Move
+	Weight float64
+}
+
+// defaultMoves - Перемещение и обмен предметов с равной вероятностью
+var defaultMoves = []WeightedMove{
+	WeightedMove{Move: ShiftMove{}, Weight: 1},
+	WeightedMove{Move: SwapMove{}, Weight: 1},
+}
+
+// DefaultMoves - Возвращает операторы окрестности по умолчанию
+func DefaultMoves() []WeightedMove {
+	moves := make([]WeightedMove, len(defaultMoves))
+	copy(moves, defaultMoves)
+	return moves
+}
+
+// selectMove - Выбирает оператор с вероятностью, пропорциональной
+// его весу (nil, если нет ни одного оператора с положительным весом)
+func selectMove(moves []WeightedMove, rng *rand.Rand) Move {
+	var total float64
+	var last Move
+	for _, move := range moves {
+		if move.Weight > 0 {
+			total += move.Weight
+			last = move.Move
+		}
+	}
+	if last == nil {
+		return nil
+	}
+
+	u := floatUniform(rng, 0, total)
+	for _, move := range moves {
+		if move.Weight <= 0 {
+			continue
+		}
+		if u < move.Weight {
+			return move.Move
+		}
+		u -= move.Weight
+	}
+	// из-за погрешности округления u может не попасть ни в один отрезок
+	return last
+}
+
+// propose - Предлагает изменение случайно выбранным оператором
+func propose(moves []WeightedMove, containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	move := selectMove(moves, rng)
+	if move == nil {
+		return Change{}, false
+	}
+	return move.Propose(containers, capacity, rng)
+}
+
+// neighbour - Строит соседнее решение как копию текущего с применённым
+// изменением (копию текущего, если изменения не нашлось)
+func neighbour(move Move, containers []Container, capacity int, rng *rand.Rand) []Container {
+	solution := createCopy(containers)
+	if change, ok := move.Propose(containers, capacity, rng); ok {
+		solution = change.apply(solution)
+	}
+	return solution
+}
+
+// randomPair - Возвращает два различных случайных индекса из [0, n)
+func randomPair(rng *rand.Rand, n int) (int, int) {
+	i := intUniform(rng, 0, n)
+	j := intUniform(rng, 0, n-1)
+	if j >= i {
+		j++
+	}
+	return i, j
+}
+
+// ShiftMove - Перемещение случайного предмета в другой контейнер,
+// в котором для него достаточно места
+type ShiftMove struct{}
+
+// Propose - Предлагает изменение решения
+func (ShiftMove) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		from, to := randomPair(rng, m)
+		if containers[from].Len() == 0 {
+			continue
+		}
+		i := intUniform(rng, 0, containers[from].Len())
+		item := containers[from].items[i]
+		if containers[to].Load()+item.Weight > capacity {
+			continue
+		}
+
+		var change Change
+		change.add(from, modified(containers[from], []int{i}))
+		change.add(to, modified(containers[to], nil, item))
+		return change, true
+	}
+	return Change{}, false
+}
+
+// SwapMove - Обмен двух случайных предметов разного веса
+// из разных контейнеров
+type SwapMove struct{}
+
+// Propose - Предлагает изменение решения
+func (SwapMove) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		x, y := randomPair(rng, m)
+		first, second := containers[x], containers[y]
+		if first.Len() == 0 || second.Len() == 0 {
+			continue
+		}
+		i := intUniform(rng, 0, first.Len())
+		j := intUniform(rng, 0, second.Len())
+		a, b := first.items[i], second.items[j]
+		// обмен одинаковых предметов не меняет решения
+		if a.Weight == b.Weight || first.Load()-a.Weight+b.Weight > capacity || second.Load()-b.Weight+a.Weight > capacity {
+			continue
+		}
+
+		var change Change
+		change.add(x, modified(first, []int{i}, b))
+		change.add(y, modified(second, []int{j}, a))
+		return change, true
+	}
+	return Change{}, false
+}
+
+// Swap21Move - Обмен двух предметов одного контейнера
+// на один предмет другого контейнера
+type Swap21Move struct{}
+
+// Propose - Предлагает изменение решения
+func (Swap21Move) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		x, y := randomPair(rng, m)
+		first, second := containers[x], containers[y]
+		if first.Len() < 2 || second.Len() < 1 {
+			continue
+		}
+
+		i, j := randomPair(rng, first.Len())
+		k := intUniform(rng, 0, second.Len())
+		a, b := first.items[i], first.items[j]
+		single := second.items[k]
+		if first.Load()-a.Weight-b.Weight+single.Weight > capacity ||
+			second.Load()-single.Weight+a.Weight+b.Weight > capacity {
+			continue
+		}
+
+		var change Change
+		change.add(x, modified(first, []int{i, j}, single))
+		change.add(y, modified(second, []int{k}, a, b))
+		return change, true
+	}
+	return Change{}, false
+}
+
+// Swap22Move - Обмен двух предметов одного контейнера
+// на два предмета другого контейнера
+type Swap22Move struct{}
+
+// Propose - Предлагает изменение решения
+func (Swap22Move) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		x, y := randomPair(rng, m)
+		first, second := containers[x], containers[y]
+		if first.Len() < 2 || second.Len() < 2 {
+			continue
+		}
+
+		i1, j1 := randomPair(rng, first.Len())
+		i2, j2 := randomPair(rng, second.Len())
+		a1, b1 := first.items[i1], first.items[j1]
+		a2, b2 := second.items[i2], second.items[j2]
+		first2, second2 := a1.Weight+b1.Weight, a2.Weight+b2.Weight
+		if first.Load()-first2+second2 > capacity || second.Load()-second2+first2 > capacity {
+			continue
+		}
+
+		var change Change
+		change.add(x, modified(first, []int{i1, j1}, a2, b2))
+		change.add(y, modified(second, []int{i2, j2}, a1, b1))
+		return change, true
+	}
+	return Change{}, false
+}
+
+// EjectMove - Освобождение случайного контейнера: его предметы
+// по невозрастанию веса распределяются по остальным контейнерам
+// алгоритмом BestFit
+type EjectMove struct{}
+
+// Propose - Предлагает изменение решения
+func (EjectMove) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+
+	e := intUniform(rng, 0, m)
+	if containers[e].Len() == 0 {
+		return Change{}, false
+	}
+
+	// загрузка контейнеров: сначала остальные контейнеры решения,
+	// затем открытые при распределении новые контейнеры
+	loads := make([]int, m)
+	for i, container := range containers {
+		if i != e {
+			loads[i] = container.Load()
+		}
+	}
+	// предметы, добавленные в каждый из контейнеров
+	added := map[int][]Item{}
+
+	for _, item := range sortItemsDecreasing(containers[e].items) {
+		minDelta, minI := capacity+1, -1
+		for i, load := range loads {
+			if i == e {
+				continue
+			}
+			delta := capacity - (load + item.Weight)
+			if delta >= 0 && delta < minDelta {
+				minDelta = delta
+				minI = i
+			}
+		}
+		if minI == -1 {
+			loads = append(loads, 0)
+			minI = len(loads) - 1
+		}
+		loads[minI] += item.Weight
+		added[minI] = append(added[minI], item)
+	}
+
+	var change Change
+	change.add(e, New())
+	for i := 0; i < len(loads); i++ {
+		items, ok := added[i]
+		if !ok {
+			continue
+		}
+		if i < m {
+			change.add(i, modified(containers[i], nil, items...))
+		} else {
+			change.add(-1, modified(New(), nil, items...))
+		}
+	}
+	return change, true
+}
+
+// ChainMove - Цепочка перемещений: предмет переносится в другой
+// контейнер, и если тот переполняется, то один из его предметов
+// переносится в следующий контейнер, и так далее. Все контейнеры
+// цепочки различны
+type ChainMove struct {
+	// Length - наибольшее число перемещений в цепочке (по умолчанию 3)
+	Length int
+}
+
+// Propose - Предлагает изменение решения
+func (move ChainMove) Propose(containers []Container, capacity int, rng *rand.Rand) (Change, bool) {
+	m := len(containers)
+	if m < 2 {
+		return Change{}, false
+	}
+	length := move.Length
+	if length <= 0 {
+		length = 3
+	}
+
+	// звено цепочки: контейнер и индекс предмета, который
+	// из него забирается
+	type link struct {
+		container int
+		index     int
+	}
+
+	for attempt := 0; attempt < moveAttempts; attempt++ {
+		from := intUniform(rng, 0, m)
+		if containers[from].Len() == 0 {
+			continue
+		}
+		chain := []link{link{container: from, index: intUniform(rng, 0, containers[from].Len())}}
+		visited := map[int]bool{from: true}
+
+		// конец цепочки: контейнер, в который
+		// последний предмет влез без переполнения
+		end := -1
+		for step := 0; step < length && len(visited) < m; step++ {
+			last := chain[len(chain)-1]
+			weight := containers[last.container].items[last.index].Weight
+
+			to := intUniform(rng, 0, m)
+			for visited[to] {
+				to = intUniform(rng, 0, m)
+			}
+			visited[to] = true
+
+			overflow := containers[to].Load() + weight - capacity
+			if overflow <= 0 {
+				end = to
+				break
+			}
+
+			// предметы, после удаления которых контейнер не переполнен
+			var candidates []int
+			for i, candidate := range containers[to].items {
+				if candidate.Weight >= overflow {
+					candidates = append(candidates, i)
+				}
+			}
+			if len(candidates) == 0 {
+				break
+			}
+			chain = append(chain, link{container: to, index: candidates[intUniform(rng, 0, len(candidates))]})
+		}
+		if end == -1 {
+			continue
+		}
+
+		// каждый контейнер цепочки отдаёт свой предмет
+		// и получает предмет предыдущего
+		var change Change
+		for i, l := range chain {
+			var added []Item
+			if i > 0 {
+				previous := chain[i-1]
+				added = append(added, containers[previous.container].items[previous.index])
+			}
+			change.add(l.container, modified(containers[l.container], []int{l.index}, added...))
+		}
+		last := chain[len(chain)-1]
+		change.add(end, modified(containers[end], nil, containers[last.container].items[last.index]))
+		return change, true
+	}
+	return Change{}, false
+}
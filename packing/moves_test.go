@@ -0,0 +1,121 @@
+package packing
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSelectMove(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	moves := []WeightedMove{
+		WeightedMove{ Move: SwapMove{}, Weight: 0 },
+		WeightedMove{ Move: EjectMove{}, Weight: 2 },
+		WeightedMove{ Move: ShiftMove{}, Weight: -1 },
+	}
+	for i := 0; i < 100; i++ {
+		if move := selectMove(moves, rng); move != (EjectMove{}) {
+			t.Fatal("result:", move, "| expected:", EjectMove{})
+		}
+	}
+	if move := selectMove(nil, rng); move != nil {
+		t.Error("result:", move, "| expected: nil")
+	}
+}
+
+func TestEjectMove(t *testing.T) {
+	// какой бы контейнер ни был освобождён,
+	// его предмет помещается в один из оставшихся
+	containers := []Container{
+		fromWeights(5),
+		fromWeights(3),
+		fromWeights(2),
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		result := neighbour(EjectMove{}, containers, 10, rng)
+		if len(result) != 2 || Validate([]int{ 5, 3, 2 }, 10, result) != nil {
+			t.Error("result:", result)
+		}
+	}
+	if len(containers) != 3 || containers[0].Len() != 1 {
+		t.Error("initial solution was modified:", containers)
+	}
+}
+
+func TestSwapMoves(t *testing.T) {
+	// единственный допустимый обмен 2 на 1 и 2 на 2 -
+	// это обмен двух предметов 1 на предмет 2 или 1 и 1
+	samples := []struct {
+		move Move
+		containers []Container
+		expected []Container
+	}{
+		{
+			Swap21Move{},
+			[]Container{
+				fromWeights(1, 1),
+				fromWeights(2),
+			},
+			[]Container{
+				fromWeights(2),
+				fromWeights(1, 1),
+			},
+		}, {
+			Swap22Move{},
+			[]Container{
+				fromWeights(3, 3),
+				fromWeights(2, 4),
+			},
+			[]Container{
+				fromWeights(2, 4),
+				fromWeights(3, 3),
+			},
+		},
+	}
+
+	for _, sample := range samples {
+		result := neighbour(sample.move, sample.containers, 6, rand.New(rand.NewSource(1)))
+		if len(result) != len(sample.expected) {
+			t.Fatal(sample.move, "result:", result, "| expected:", sample.expected)
+		}
+		// порядок предметов внутри контейнера не важен
+		for i, container := range result {
+			if Validate(sample.expected[i].Weights(), 6, []Container{ container }) != nil {
+				t.Error(sample.move, "result:", result, "| expected:", sample.expected)
+			}
+		}
+	}
+}
+
+func TestChainMove(t *testing.T) {
+	// 4 можно перенести только цепочкой: 4 во второй контейнер,
+	// а вытесненный из него 3 - в третий
+	containers := []Container{
+		fromWeights(4),
+		fromWeights(3, 3),
+		fromWeights(6),
+	}
+	result := neighbour(ChainMove{}, containers, 10, rand.New(rand.NewSource(1)))
+	if Validate([]int{ 4, 3, 3, 6 }, 10, result) != nil {
+		t.Error("invalid packing:", result)
+	}
+}
+
+func TestMovesKeepItems(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	moves := []Move{ ShiftMove{}, SwapMove{}, Swap21Move{}, Swap22Move{}, EjectMove{}, ChainMove{}, ChainMove{ Length: 1 } }
+	for _, move := range moves {
+		solution := bestFit(exampleWeights, 150)
+		for i := 0; i < 200; i++ {
+			solution = neighbour(move, solution, 150, rng)
+			if Validate(exampleWeights, 150, solution) != nil {
+				t.Fatal(move, "step:", i, "| invalid packing:", solution)
+			}
+			for _, container := range solution {
+				if container.Len() == 0 {
+					t.Fatal(move, "step:", i, "| empty container:", solution)
+				}
+			}
+		}
+	}
+}
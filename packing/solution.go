@@ -0,0 +1,34 @@
+package packing
+
+// Solution - Решение задачи упаковки: заполненные
+// предметами контейнеры и их вместимость
+type Solution struct {
+	Containers []Container
+	Capacity   int
+}
+
+// NumBins - Возвращает количество контейнеров
+func (solution Solution) NumBins() int {
+	return len(solution.Containers)
+}
+
+// FillRatio - Вычисляет заполненность контейнеров: отношение
+// суммарного веса предметов к суммарной вместимости контейнеров
+func (solution Solution) FillRatio() float64 {
+	if len(solution.Containers) == 0 || solution.Capacity <= 0 {
+		return 0
+	}
+	total := len(solution.Containers) * solution.Capacity
+	return float64(total-calculatePadding(solution.Containers, solution.Capacity)) / float64(total)
+}
+
+// Validate - Проверяет допустимость решения для предметов items,
+// см. ValidateItems
+func (solution Solution) Validate(items []Item) error {
+	return ValidateItems(items, solution.Capacity, solution.Containers)
+}
+
+// Clone - Возвращает независимую копию решения
+func (solution Solution) Clone() Solution {
+	return Solution{Containers: createCopy(solution.Containers), Capacity: solution.Capacity}
+}
@@ -0,0 +1,74 @@
+package packing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolution(t *testing.T) {
+	solution := Solution{
+		Containers: []Container{
+			fromWeights(4, 6),
+			fromWeights(5),
+		},
+		Capacity: 10,
+	}
+	if solution.NumBins() != 2 {
+		t.Error("bins:", solution.NumBins(), "| expected:", 2)
+	}
+	if ratio := solution.FillRatio(); math.Abs(ratio-0.75) > 1e-9 {
+		t.Error("fill ratio:", ratio, "| expected:", 0.75)
+	}
+	if ratio := (Solution{ Capacity: 10 }).FillRatio(); ratio != 0 {
+		t.Error("empty fill ratio:", ratio, "| expected:", 0)
+	}
+
+	clone := solution.Clone()
+	clone.Containers[0].append(Item{ ID: 2, Weight: 1 })
+	if solution.Containers[0].Len() != 2 || solution.Containers[0].Load() != 10 {
+		t.Error("clone shares containers:", solution.Containers)
+	}
+}
+
+func TestSolutionValidate(t *testing.T) {
+	items := NewItems([]int{ 4, 6, 5 })
+	samples := []struct {
+		solution Solution
+		valid bool
+	}{
+		{
+			Solution{ Containers: renumber(fromWeights(4, 6), fromWeights(5)), Capacity: 10 },
+			true,
+		}, {
+			Solution{ Containers: renumber(fromWeights(4, 6, 5)), Capacity: 10 },
+			false,
+		}, {
+			Solution{ Containers: renumber(fromWeights(4, 6)), Capacity: 10 },
+			false,
+		},
+	}
+
+	for _, sample := range samples {
+		err := sample.solution.Validate(items)
+		if (err == nil) != sample.valid {
+			t.Error("solution:", sample.solution, "| error:", err, "| expected valid:", sample.valid)
+		}
+	}
+}
+
+func TestContainerItems(t *testing.T) {
+	container := fromWeights(3, 1, 2)
+	items := container.Items()
+	if container.Len() != 3 || len(items) != 3 || items[0].Weight != 3 || items[1].Weight != 1 || items[2].Weight != 2 {
+		t.Error("items:", items, "| len:", container.Len())
+	}
+	ids := container.IDs()
+	if len(ids) != 3 || ids[0] != 0 || ids[1] != 1 || ids[2] != 2 {
+		t.Error("ids:", ids)
+	}
+	// изменение возвращённого среза не затрагивает контейнер
+	items[0].Weight = 100
+	if container.items[0].Weight != 3 {
+		t.Error("container was modified:", container.items)
+	}
+}
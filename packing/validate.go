@@ -0,0 +1,199 @@
+package packing
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ViolationKind - Вид нарушения допустимости решения
+type ViolationKind int
+
+const (
+	// Overflow - суммарный вес предметов превышает вместимость контейнера
+	Overflow ViolationKind = iota
+	// EmptyContainer - в решении есть пустой контейнер
+	EmptyContainer
+	// MissingItem - предмет не упакован ни в один контейнер
+	MissingItem
+	// ExtraItem - предмет упакован больше одного раза
+	// или отсутствует во входных данных
+	ExtraItem
+	// WeightMismatch - вес упакованного предмета отличается
+	// от веса предмета с тем же идентификатором во входных данных
+	WeightMismatch
+)
+
+// String - Возвращает название вида нарушения
+func (kind ViolationKind) String() string {
+	switch kind {
+	case Overflow:
+		return "переполнение контейнера"
+	case EmptyContainer:
+		return "пустой контейнер"
+	case MissingItem:
+		return "предмет не упакован"
+	case ExtraItem:
+		return "лишний предмет"
+	case WeightMismatch:
+		return "неверный вес предмета"
+	}
+	return fmt.Sprintf("нарушение %d", int(kind))
+}
+
+// Violation - Нарушение допустимости решения
+type Violation struct {
+	Kind ViolationKind
+	// Container - индекс контейнера (-1, если предмет не упакован)
+	Container int
+	// ID - идентификатор предмета (кроме переполнения и пустого контейнера)
+	ID int
+	// Weight - вес предмета, а для переполнения - загрузка контейнера
+	Weight int
+}
+
+// String - Описывает нарушение
+func (violation Violation) String() string {
+	switch violation.Kind {
+	case Overflow:
+		return fmt.Sprintf("%v %d: загрузка %d", violation.Kind, violation.Container, violation.Weight)
+	case EmptyContainer:
+		return fmt.Sprintf("%v %d", violation.Kind, violation.Container)
+	case MissingItem:
+		return fmt.Sprintf("%v %d: вес %d", violation.Kind, violation.ID, violation.Weight)
+	}
+	return fmt.Sprintf("%v %d в контейнере %d: вес %d", violation.Kind, violation.ID, violation.Container, violation.Weight)
+}
+
+// ValidationError - Ошибка проверки решения: все найденные нарушения
+type ValidationError struct {
+	Capacity   int
+	Violations []Violation
+}
+
+// Error - Описывает все нарушения
+func (err *ValidationError) Error() string {
+	descriptions := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		descriptions[i] = violation.String()
+	}
+	return fmt.Sprintf("решение недопустимо (вместимость %d): %s", err.Capacity, strings.Join(descriptions, "; "))
+}
+
+/*
+	Validate
+	Проверка допустимости решения: ни один контейнер не переполнен
+	и не пуст, каждый предмет упакован ровно один раз. Предметы
+	сравниваются по весу, поэтому предметы равного веса взаимозаменяемы;
+	не упакованным предметам приписываются идентификаторы NewItems
+	входные данные:
+		weights - веса предметов исходной задачи
+		capacity - вместимость контейнеров
+		containers - проверяемое решение
+	выходные данные:
+		nil для допустимого решения, иначе *ValidationError
+		со всеми найденными нарушениями
+*/
+func Validate(weights []int, capacity int, containers []Container) error {
+	var violations []Violation
+
+	// число ещё не найденных в решении предметов каждого веса
+	remaining := map[int]int{}
+	for _, weight := range weights {
+		remaining[weight]++
+	}
+
+	for i, container := range containers {
+		if container.Len() == 0 {
+			violations = append(violations, Violation{Kind: EmptyContainer, Container: i})
+		}
+		if container.Load() > capacity {
+			violations = append(violations, Violation{Kind: Overflow, Container: i, Weight: container.Load()})
+		}
+		for _, item := range container.items {
+			if remaining[item.Weight] == 0 {
+				violations = append(violations, Violation{Kind: ExtraItem, Container: i, ID: item.ID, Weight: item.Weight})
+				continue
+			}
+			remaining[item.Weight]--
+		}
+	}
+
+	// не упакованными считаются последние предметы каждого веса,
+	// они перечисляются в порядке входных данных
+	skip := map[int]int{}
+	for _, weight := range weights {
+		skip[weight]++
+	}
+	for weight, count := range remaining {
+		skip[weight] -= count
+	}
+	for i, weight := range weights {
+		if skip[weight] > 0 {
+			skip[weight]--
+			continue
+		}
+		violations = append(violations, Violation{Kind: MissingItem, Container: -1, ID: i, Weight: weight})
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Capacity: capacity, Violations: violations}
+	}
+	return nil
+}
+
+/*
+	ValidateItems
+	Проверка допустимости решения для предметов с идентификаторами:
+	ни один контейнер не переполнен и не пуст, каждый предмет упакован
+	ровно один раз и со своим весом. Предметы сравниваются по
+	идентификатору, поэтому предметы равного веса не взаимозаменяемы
+	входные данные:
+		items - предметы исходной задачи
+		capacity - вместимость контейнеров
+		containers - проверяемое решение
+	выходные данные:
+		nil для допустимого решения, иначе *ValidationError
+		со всеми найденными нарушениями
+*/
+func ValidateItems(items []Item, capacity int, containers []Container) error {
+	var violations []Violation
+
+	weights := make(map[int]int, len(items))
+	for _, item := range items {
+		weights[item.ID] = item.Weight
+	}
+	packed := make(map[int]bool, len(items))
+
+	for i, container := range containers {
+		if container.Len() == 0 {
+			violations = append(violations, Violation{Kind: EmptyContainer, Container: i})
+		}
+		if container.Load() > capacity {
+			violations = append(violations, Violation{Kind: Overflow, Container: i, Weight: container.Load()})
+		}
+		for _, item := range container.items {
+			weight, ok := weights[item.ID]
+			switch {
+			case !ok || packed[item.ID]:
+				violations = append(violations, Violation{Kind: ExtraItem, Container: i, ID: item.ID, Weight: item.Weight})
+				continue
+			case item.Weight != weight:
+				violations = append(violations, Violation{Kind: WeightMismatch, Container: i, ID: item.ID, Weight: item.Weight})
+			}
+			packed[item.ID] = true
+		}
+	}
+
+	// не упакованные предметы перечисляются в порядке входных данных
+	for _, item := range items {
+		if !packed[item.ID] {
+			violations = append(violations, Violation{Kind: MissingItem, Container: -1, ID: item.ID, Weight: item.Weight})
+			packed[item.ID] = true
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Capacity: capacity, Violations: violations}
+	}
+	return nil
+}
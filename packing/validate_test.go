@@ -0,0 +1,167 @@
+package packing
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	samples := []struct {
+		weights []int
+		containers []Container
+		violations []Violation
+	}{
+		{
+			[]int{ 4, 6, 5 },
+			[]Container{ fromWeights(4, 6), fromWeights(5) },
+			nil,
+		}, {
+			[]int{ 4, 7 },
+			[]Container{ fromWeights(4, 7) },
+			[]Violation{ Violation{ Kind: Overflow, Container: 0, Weight: 11 } },
+		}, {
+			[]int{ 4 },
+			[]Container{ fromWeights(4), New() },
+			[]Violation{ Violation{ Kind: EmptyContainer, Container: 1 } },
+		}, {
+			[]int{ 4, 4, 3 },
+			[]Container{ fromWeights(4) },
+			[]Violation{
+				Violation{ Kind: MissingItem, Container: -1, ID: 1, Weight: 4 },
+				Violation{ Kind: MissingItem, Container: -1, ID: 2, Weight: 3 },
+			},
+		}, {
+			[]int{ 4, 3 },
+			[]Container{ fromWeights(4, 3), fromWeights(3, 2) },
+			[]Violation{
+				Violation{ Kind: ExtraItem, Container: 1, ID: 0, Weight: 3 },
+				Violation{ Kind: ExtraItem, Container: 1, ID: 1, Weight: 2 },
+			},
+		},
+	}
+
+	for _, sample := range samples {
+		checkViolations(t, Validate(sample.weights, 10, sample.containers), sample.violations)
+	}
+}
+
+// checkViolations - Сравнивает нарушения из ошибки проверки с ожидаемыми
+func checkViolations(t *testing.T, err error, expected []Violation) {
+	t.Helper()
+	if expected == nil {
+		if err != nil {
+			t.Error("unexpected error:", err)
+		}
+		return
+	}
+	validationError, ok := err.(*ValidationError)
+	if !ok {
+		t.Error("result:", err, "| expected:", expected)
+		return
+	}
+	if len(validationError.Violations) != len(expected) {
+		t.Error("result:", validationError.Violations, "| expected:", expected)
+		return
+	}
+	for i, violation := range validationError.Violations {
+		if violation != expected[i] {
+			t.Error("result:", violation, "| expected:", expected[i])
+		}
+	}
+}
+
+func TestValidateItems(t *testing.T) {
+	items := []Item{
+		Item{ ID: 10, Weight: 4 },
+		Item{ ID: 11, Weight: 4 },
+		Item{ ID: 12, Weight: 6 },
+	}
+	pack := func(items ...Item) Container {
+		container := New()
+		for _, item := range items {
+			container.append(item)
+		}
+		return container
+	}
+
+	samples := []struct {
+		containers []Container
+		violations []Violation
+	}{
+		{
+			[]Container{ pack(items[0], items[2]), pack(items[1]) },
+			nil,
+		}, {
+			// предметы равного веса не взаимозаменяемы
+			[]Container{ pack(items[0], items[2]), pack(items[0]) },
+			[]Violation{
+				Violation{ Kind: ExtraItem, Container: 1, ID: 10, Weight: 4 },
+				Violation{ Kind: MissingItem, Container: -1, ID: 11, Weight: 4 },
+			},
+		}, {
+			[]Container{ pack(items[0], Item{ ID: 12, Weight: 5 }), pack(items[1], Item{ ID: 13, Weight: 1 }) },
+			[]Violation{
+				Violation{ Kind: WeightMismatch, Container: 0, ID: 12, Weight: 5 },
+				Violation{ Kind: ExtraItem, Container: 1, ID: 13, Weight: 1 },
+			},
+		}, {
+			[]Container{ pack(items...), New() },
+			[]Violation{
+				Violation{ Kind: Overflow, Container: 0, Weight: 14 },
+				Violation{ Kind: EmptyContainer, Container: 1 },
+			},
+		},
+	}
+
+	for _, sample := range samples {
+		checkViolations(t, ValidateItems(items, 10, sample.containers), sample.violations)
+	}
+}
+
+// randomInstance - Генерирует случайные веса в [1, capacity]
+func randomInstance(rng *rand.Rand, n, capacity int) []int {
+	weights := make([]int, n)
+	for i := range weights {
+		weights[i] = 1 + rng.Intn(capacity)
+	}
+	return weights
+}
+
+func TestBestFitValid(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for i := 0; i < 200; i++ {
+		capacity := 1 + rng.Intn(200)
+		weights := randomInstance(rng, 1+rng.Intn(100), capacity)
+		if err := Validate(weights, capacity, bestFit(weights, capacity)); err != nil {
+			t.Fatal("weights:", weights, "| capacity:", capacity, "|", err)
+		}
+	}
+}
+
+func TestSimulatedAnnealingValid(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	moves := []WeightedMove{
+		WeightedMove{ Move: ShiftMove{}, Weight: 1 },
+		WeightedMove{ Move: SwapMove{}, Weight: 1 },
+		WeightedMove{ Move: Swap21Move{}, Weight: 1 },
+		WeightedMove{ Move: Swap22Move{}, Weight: 1 },
+		WeightedMove{ Move: EjectMove{}, Weight: 1 },
+		WeightedMove{ Move: ChainMove{}, Weight: 1 },
+	}
+	for i := 0; i < 30; i++ {
+		capacity := 1 + rng.Intn(200)
+		weights := randomInstance(rng, 1+rng.Intn(60), capacity)
+		// на плато отжиг может не остановиться по счётчику P,
+		// поэтому время работы ограничено
+		options := AnnealOptions{Temperature: 10, CoolingRate: 0.8, Steps: 50, StagnationLimit: 3,
+			Seed: int64(i), Moves: moves, Energy: FillSquaredEnergy{}, TimeLimit: 20 * time.Millisecond}
+		result, err := SimulatedAnnealing(weights, capacity, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Validate(weights, capacity, result.Containers); err != nil {
+			t.Fatal("weights:", weights, "| capacity:", capacity, "|", err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"./packing"
+)
+
+// Задача упаковки
+type instance struct {
+	name     string
+	capacity int
+	items    []packing.Item
+	// best - оптимальное или лучшее известное число
+	// контейнеров (0 - неизвестно)
+	best int
+}
+
+// Форматы входных данных
+var inputFormats = []string{"auto", "text", "json", "orlib", "bpplib"}
+
+// Выполняет чтение задач из файла в заданном формате:
+//
+//	text   - формат readData: вместимость, число предметов и их веса
+//	json   - формат decodeInstance
+//	orlib  - формат OR-Library с несколькими задачами в одном файле
+//	bpplib - формат BPPLIB, возможно с кратностями предметов
+//	auto   - json для файлов .json, bpplib для файлов .bpp, иначе text
+//
+// В текстовых форматах идентификатор предмета - его номер в задаче
+func readInstances(filename string, format string) ([]instance, error) {
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if format == "auto" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".json":
+			format = "json"
+		case ".bpp":
+			format = "bpplib"
+		default:
+			format = "text"
+		}
+	}
+
+	if format == "text" {
+		weights, capacity, err := readData(filename)
+		if err != nil {
+			return nil, err
+		}
+		return []instance{instance{name: name, capacity: capacity, items: packing.NewItems(weights)}}, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch format {
+	case "json":
+		result, err := decodeInstance(file)
+		if result.name == "" {
+			result.name = name
+		}
+		return []instance{result}, err
+	case "orlib":
+		return parseORLibrary(file)
+	case "bpplib":
+		result, err := parseBPPLIB(file, name)
+		return []instance{result}, err
+	}
+	return nil, fmt.Errorf("неизвестный формат входных данных: %q", format)
+}
+
+// Возвращает все слова текста, разделённые пробельными символами
+func readWords(reader io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanWords)
+	var words []string
+	for scanner.Scan() {
+		words = append(words, scanner.Text())
+	}
+	return words, scanner.Err()
+}
+
+// Переводит неотрицательные десятичные числа в целые, умножая
+// все числа на одну и ту же степень десяти так, чтобы
+// дробные части исчезли: "100.0", "49.1" -> 1000, 491
+func scaleNumbers(numbers []string) ([]int, error) {
+	decimals := 0
+	for _, number := range numbers {
+		if i := strings.IndexByte(number, '.'); i >= 0 {
+			if fraction := strings.TrimRight(number[i+1:], "0"); len(fraction) > decimals {
+				decimals = len(fraction)
+			}
+		}
+	}
+
+	result := make([]int, len(numbers))
+	for i, number := range numbers {
+		integer, fraction := number, ""
+		if j := strings.IndexByte(number, '.'); j >= 0 {
+			integer, fraction = number[:j], strings.TrimRight(number[j+1:], "0")
+		}
+		value, err := strconv.Atoi(integer + fraction + strings.Repeat("0", decimals-len(fraction)))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("неверное число: %q", number)
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+/*
+	Разбор файла OR-Library (binpack1.txt - binpack8.txt)
+	входные данные:
+		reader - текст вида
+			P               - число задач
+			name            - идентификатор задачи
+			W n best        - вместимость, число предметов и
+			                  число контейнеров лучшего известного решения
+			w1 ... wn       - веса предметов
+			name ...        - следующая задача
+		вместимость и веса могут быть дробными (наборы триплетов),
+		тогда они умножаются на общую степень десяти
+	выходные данные:
+		задачи файла
+*/
+func parseORLibrary(reader io.Reader) ([]instance, error) {
+	words, err := readWords(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("пустой файл OR-Library")
+	}
+	count, err := strconv.Atoi(words[0])
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("неверное число задач: %q", words[0])
+	}
+	words = words[1:]
+
+	instances := make([]instance, 0, count)
+	for p := 0; p < count; p++ {
+		if len(words) < 4 {
+			return nil, fmt.Errorf("задача %d: неожиданный конец файла", p+1)
+		}
+		name := words[0]
+		n, err := strconv.Atoi(words[2])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("задача %s: неверное число предметов: %q", name, words[2])
+		}
+		best, err := strconv.Atoi(words[3])
+		if err != nil || best < 0 {
+			return nil, fmt.Errorf("задача %s: неверное число контейнеров: %q", name, words[3])
+		}
+		if len(words) < 4+n {
+			return nil, fmt.Errorf("задача %s: неожиданный конец файла", name)
+		}
+
+		// вместимость масштабируется вместе с весами
+		numbers := append([]string{words[1]}, words[4:4+n]...)
+		values, err := scaleNumbers(numbers)
+		if err != nil {
+			return nil, fmt.Errorf("задача %s: %v", name, err)
+		}
+		instances = append(instances, instance{name: name, capacity: values[0], items: packing.NewItems(values[1:]), best: best})
+		words = words[4+n:]
+	}
+	return instances, nil
+}
+
+/*
+	Разбор файла BPPLIB
+	входные данные:
+		reader - текст вида
+			n      - число предметов (или типов предметов)
+			W      - вместимость
+			w1     - веса предметов по одному в строке
+			...
+		либо
+			w1 d1  - вес и кратность каждого типа предметов
+			...
+		name - идентификатор задачи
+	выходные данные:
+		задача, в которой предметы одного типа повторяются
+		столько раз, какова их кратность
+*/
+func parseBPPLIB(reader io.Reader, name string) (instance, error) {
+	words, err := readWords(reader)
+	if err != nil {
+		return instance{}, err
+	}
+	numbers := make([]int, len(words))
+	for i, word := range words {
+		numbers[i], err = strconv.Atoi(word)
+		if err != nil || numbers[i] < 0 {
+			return instance{}, fmt.Errorf("задача %s: неверное число: %q", name, word)
+		}
+	}
+	if len(numbers) < 2 {
+		return instance{}, fmt.Errorf("задача %s: неожиданный конец файла", name)
+	}
+
+	n, capacity, rest := numbers[0], numbers[1], numbers[2:]
+	var weights []int
+	switch len(rest) {
+	case n:
+		weights = rest
+	case 2 * n:
+		for i := 0; i < n; i++ {
+			for d := 0; d < rest[2*i+1]; d++ {
+				weights = append(weights, rest[2*i])
+			}
+		}
+	default:
+		return instance{}, fmt.Errorf("задача %s: ожидалось %d или %d чисел после вместимости, получено %d",
+			name, n, 2*n, len(rest))
+	}
+	return instance{name: name, capacity: capacity, items: packing.NewItems(weights)}, nil
+}
+
+// Проверяет входные данные всех задач; в ошибке
+// указываются название задачи и идентификатор предмета
+func checkInstances(instances []instance) error {
+	for _, inst := range instances {
+		if err := packing.CheckItems(inst.items, inst.capacity); err != nil {
+			return fmt.Errorf("%s: %v", inst.name, err)
+		}
+	}
+	return nil
+}
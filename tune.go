@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"./packing"
+)
+
+// Диапазон значений параметра отжига
+type paramRange struct {
+	// values - значения для перебора по сетке
+	values []float64
+	// min, max - границы для случайного поиска: если continuous,
+	// то значение выбирается на отрезке, иначе - из values
+	min, max   float64
+	continuous bool
+}
+
+// Разбирает диапазон значений параметра:
+//
+//	"a"         - одно значение
+//	"a,b,c"     - список значений
+//	"min:max:k" - k равноотстоящих значений на отрезке [min, max]
+func parseRange(spec string) (paramRange, error) {
+	if parts := strings.Split(spec, ":"); len(parts) == 3 {
+		min, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil {
+			return paramRange{}, fmt.Errorf("неверный диапазон %q: %v", spec, err)
+		}
+		max, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return paramRange{}, fmt.Errorf("неверный диапазон %q: %v", spec, err)
+		}
+		k, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+		if err != nil || k < 1 || min > max {
+			return paramRange{}, fmt.Errorf("неверный диапазон %q", spec)
+		}
+		result := paramRange{min: min, max: max, continuous: true}
+		for i := 0; i < k; i++ {
+			value := min
+			if k > 1 {
+				value += (max - min) * float64(i) / float64(k-1)
+			}
+			result.values = append(result.values, value)
+		}
+		return result, nil
+	}
+
+	var result paramRange
+	for _, field := range strings.Split(spec, ",") {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return paramRange{}, fmt.Errorf("неверный диапазон %q: %v", spec, err)
+		}
+		result.values = append(result.values, value)
+	}
+	return result, nil
+}
+
+// Возвращает случайное значение из диапазона; значения
+// с отрезка округляются до трёх значащих цифр
+func (p paramRange) sample(rng *rand.Rand) float64 {
+	if p.continuous {
+		value := p.min + (p.max-p.min)*rng.Float64()
+		value, _ = strconv.ParseFloat(strconv.FormatFloat(value, 'g', 3, 64), 64)
+		return math.Max(p.min, math.Min(p.max, value))
+	}
+	return p.values[rng.Intn(len(p.values))]
+}
+
+// Округляет значение целочисленного параметра (не меньше 1)
+func roundParam(value float64) int {
+	if rounded := int(math.Round(value)); rounded > 1 {
+		return rounded
+	}
+	return 1
+}
+
+/*
+	Наборы параметров отжига для настройки
+	входные данные:
+		search - grid (все сочетания значений) или random
+		samples - число случайных наборов (только для random)
+		T, r, L, E - диапазоны параметров; L и E округляются
+		rng - источник случайных чисел (только для random)
+	выходные данные:
+		наборы параметров без повторений
+*/
+func tuneConfigs(search string, samples int, T, r, L, E paramRange, rng *rand.Rand) ([]packing.AnnealOptions, error) {
+	var configs []packing.AnnealOptions
+	type key struct {
+		T, r float64
+		L, E int
+	}
+	seen := map[key]bool{}
+	add := func(T, r, L, E float64) {
+		k := key{T, r, roundParam(L), roundParam(E)}
+		if !seen[k] {
+			seen[k] = true
+			configs = append(configs, packing.AnnealOptions{Temperature: k.T, CoolingRate: k.r, Steps: k.L, StagnationLimit: k.E})
+		}
+	}
+
+	switch search {
+	case "grid":
+		for _, t := range T.values {
+			for _, rate := range r.values {
+				for _, l := range L.values {
+					for _, e := range E.values {
+						add(t, rate, l, e)
+					}
+				}
+			}
+		}
+	case "random":
+		for i := 0; i < samples; i++ {
+			add(T.sample(rng), r.sample(rng), L.sample(rng), E.sample(rng))
+		}
+	default:
+		return nil, fmt.Errorf("неизвестный способ поиска: %q", search)
+	}
+	return configs, nil
+}
+
+// Итоги запусков отжига с одним набором параметров
+type tuneResult struct {
+	param packing.AnnealOptions
+	// totals - суммарное по всем задачам число контейнеров
+	// для каждого зерна
+	totals []int
+	// runs и elapsed - число запусков и их суммарное время
+	runs    int
+	elapsed time.Duration
+}
+
+// Среднее суммарного числа контейнеров
+func (t tuneResult) mean() float64 {
+	sum := 0
+	for _, total := range t.totals {
+		sum += total
+	}
+	return float64(sum) / float64(len(t.totals))
+}
+
+// Выборочная дисперсия суммарного числа контейнеров
+// (0 для одного зерна)
+func (t tuneResult) variance() float64 {
+	if len(t.totals) < 2 {
+		return 0
+	}
+	mean := t.mean()
+	sum := 0.0
+	for _, total := range t.totals {
+		sum += (float64(total) - mean) * (float64(total) - mean)
+	}
+	return sum / float64(len(t.totals)-1)
+}
+
+/*
+	Настройка параметров отжига
+	входные данные:
+		ctx - контекст, при отмене которого настройка прерывается
+		instances - задачи
+		configs - наборы параметров
+		seeds - число запусков каждого набора на каждой задаче
+		        с зёрнами seed, seed+1, ...
+		anneal - флаги отжига (энергия, операторы окрестности и т.д.)
+		timeLimit - ограничение времени одного запуска
+	выходные данные:
+		итоги наборов, упорядоченные по среднему числу контейнеров,
+		затем по дисперсии и по времени; при прерывании - итоги
+		полностью проверенных наборов и ошибка контекста
+*/
+func runTune(ctx context.Context, instances []instance, configs []packing.AnnealOptions, seeds int, seed int64,
+	anneal *annealFlags, timeLimit time.Duration) ([]tuneResult, error) {
+	var results []tuneResult
+	var err error
+	for _, config := range configs {
+		result := tuneResult{param: config, totals: make([]int, seeds)}
+		for i := 0; i < seeds && err == nil; i++ {
+			for _, inst := range instances {
+				// закон охлаждения строится по коэффициенту набора
+				var options packing.AnnealOptions
+				if options, err = anneal.options(config); err != nil {
+					break
+				}
+				options.Seed = seed + int64(i)
+				options.TimeLimit = timeLimit
+				var r report
+				if r, err = runAlgorithm(ctx, "anneal", inst, options, timeLimit); err != nil {
+					break
+				}
+				result.totals[i] += r.solution.NumBins()
+				result.runs++
+				result.elapsed += r.elapsed
+			}
+		}
+		if err != nil {
+			break
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.mean() != b.mean() {
+			return a.mean() < b.mean()
+		}
+		if a.variance() != b.variance() {
+			return a.variance() < b.variance()
+		}
+		return a.elapsed < b.elapsed
+	})
+	return results, err
+}
+
+// Выводит итоги настройки: top лучших наборов (0 - все) со средним
+// временем одного запуска и флаги командной строки для лучшего набора
+func writeTune(writer io.Writer, results []tuneResult, top int) error {
+	var b strings.Builder
+	if top <= 0 || top > len(results) {
+		top = len(results)
+	}
+	b.WriteString("| T | r | L | E | Среднее | Дисперсия | Время, мс |\n")
+	b.WriteString("|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, r := range results[:top] {
+		fmt.Fprintf(&b, "| %g | %g | %d | %d | %.2f | %.2f | %.3f |\n", r.param.Temperature, r.param.CoolingRate,
+			r.param.Steps, r.param.StagnationLimit, r.mean(), r.variance(),
+			float64(r.elapsed)/float64(r.runs)/float64(time.Millisecond))
+	}
+	if len(results) > 0 {
+		best := results[0]
+		fmt.Fprintf(&b, "\nЛучшие параметры: -T %g -r %g -L %d -E %d (среднее - %.2f, дисперсия - %.2f)\n",
+			best.param.Temperature, best.param.CoolingRate, best.param.Steps, best.param.StagnationLimit,
+			best.mean(), best.variance())
+	}
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// Подкоманда tune: подбор параметров отжига T, r, L и E
+// на задачах из файлов и каталогов
+func tuneCommand(args []string) error {
+	flags := flag.NewFlagSet("tune", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Использование: tune [флаги] файл или каталог...")
+		fmt.Fprintln(flags.Output(), "Диапазон параметра: значение, список через запятую или min:max:k")
+		flags.PrintDefaults()
+	}
+	T := flags.String("T", "1000,3000,5000", "диапазон начальной температуры")
+	r := flags.String("r", "0.8,0.9,0.99", "диапазон коэффициента охлаждения")
+	L := flags.String("L", "100,300", "диапазон числа шагов при одной температуре")
+	E := flags.String("E", "5,15", "диапазон числа смен температуры без изменения решения")
+	search := flags.String("search", "grid", "способ поиска: grid или random")
+	samples := flags.Int("samples", 20, "число случайных наборов параметров (для random)")
+	seeds := flags.Int("seeds", 3, "число запусков каждого набора на каждой задаче")
+	seed := flags.Int64("seed", 1, "зерно генератора случайных чисел")
+	timeLimit := flags.Duration("time", 0, "ограничение времени одного запуска, например 10s (0 - без ограничения)")
+	top := flags.Int("top", 10, "число выводимых лучших наборов (0 - все)")
+	inputFormat := flags.String("input", "auto", "формат входных файлов: "+strings.Join(inputFormats, ", "))
+	anneal := addAnnealFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("необходимо указание файлов или каталогов с задачами")
+	}
+	if *seeds < 1 {
+		return fmt.Errorf("число запусков должно быть положительным: %d", *seeds)
+	}
+	var ranges [4]paramRange
+	for i, spec := range []string{*T, *r, *L, *E} {
+		var err error
+		if ranges[i], err = parseRange(spec); err != nil {
+			return err
+		}
+	}
+	configs, err := tuneConfigs(*search, *samples, ranges[0], ranges[1], ranges[2], ranges[3],
+		rand.New(rand.NewSource(*seed)))
+	if err != nil {
+		return err
+	}
+
+	if _, err := anneal.options(packing.AnnealOptions{}); err != nil {
+		return err
+	}
+
+	var instances []instance
+	for _, path := range flags.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		var result []instance
+		if info.IsDir() {
+			result, err = readSuite(path, *inputFormat)
+		} else {
+			if result, err = readInstances(path, *inputFormat); err == nil {
+				err = checkInstances(result)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		instances = append(instances, result...)
+	}
+
+	// по Ctrl+C выводятся итоги уже проверенных наборов
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results, tuneErr := runTune(ctx, instances, configs, *seeds, *seed, anneal, *timeLimit)
+	if err := writeTune(os.Stdout, results, *top); err != nil {
+		return err
+	}
+	return tuneErr
+}
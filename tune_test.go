@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"./packing"
+)
+
+func TestParseRange(t *testing.T) {
+	samples := []struct {
+		spec string
+		values []float64
+		continuous bool
+	}{
+		{ "1000", []float64{ 1000 }, false },
+		{ "0.8, 0.9,0.99", []float64{ 0.8, 0.9, 0.99 }, false },
+		{ "100:400:4", []float64{ 100, 200, 300, 400 }, true },
+		{ "5:10:1", []float64{ 5 }, true },
+	}
+	for _, sample := range samples {
+		result, err := parseRange(sample.spec)
+		if err != nil {
+			t.Fatal(sample.spec, err)
+		}
+		if fmt.Sprint(result.values) != fmt.Sprint(sample.values) || result.continuous != sample.continuous {
+			t.Error("spec:", sample.spec, "| result:", result, "| expected:", sample.values)
+		}
+	}
+
+	for _, spec := range []string{ "", "x", "1,x", "1:2", "2:1:3", "1:2:0", "1:2:x" } {
+		if _, err := parseRange(spec); err == nil {
+			t.Error("spec:", spec, "| expected error")
+		}
+	}
+}
+
+func TestTuneConfigs(t *testing.T) {
+	T, _ := parseRange("100,200")
+	r, _ := parseRange("0.9")
+	L, _ := parseRange("10,10.2")
+	E, _ := parseRange("0:3:2")
+
+	// значения L после округления совпадают, а E не меньше 1
+	configs, err := tuneConfigs("grid", 0, T, r, L, E, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{ "100 0.9 10 1", "100 0.9 10 3", "200 0.9 10 1", "200 0.9 10 3" }
+	if len(configs) != len(expected) {
+		t.Fatal("result:", configs)
+	}
+	for i, config := range configs {
+		result := fmt.Sprint(config.Temperature, config.CoolingRate, config.Steps, config.StagnationLimit)
+		if result != expected[i] {
+			t.Error("result:", result, "| expected:", expected[i])
+		}
+	}
+
+	T, _ = parseRange("100:1000:2")
+	configs, err = tuneConfigs("random", 50, T, r, L, E, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) == 0 || len(configs) > 50 {
+		t.Fatal("result:", configs)
+	}
+	for _, config := range configs {
+		if config.Temperature < 100 || config.Temperature > 1000 || config.Steps != 10 ||
+			config.StagnationLimit < 1 || config.StagnationLimit > 3 {
+			t.Error("result:", config)
+		}
+	}
+
+	if _, err := tuneConfigs("unknown", 1, T, r, L, E, nil); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTuneResult(t *testing.T) {
+	result := tuneResult{ totals: []int{ 10, 12, 14 } }
+	if result.mean() != 12 || math.Abs(result.variance()-4) > 1e-9 {
+		t.Error("mean:", result.mean(), "| variance:", result.variance())
+	}
+	if single := (tuneResult{ totals: []int{ 7 } }); single.variance() != 0 {
+		t.Error("variance:", single.variance())
+	}
+}
+
+func TestRunTune(t *testing.T) {
+	instances := []instance{
+		instance{ name: "a", capacity: 10, items: packing.NewItems([]int{ 6, 5, 4, 5 }) },
+		instance{ name: "b", capacity: 10, items: packing.NewItems([]int{ 3, 7, 7, 3 }) },
+	}
+	configs := []packing.AnnealOptions{
+		packing.AnnealOptions{ Temperature: 1, CoolingRate: 0.5, Steps: 5, StagnationLimit: 1 },
+		packing.AnnealOptions{ Temperature: 10, CoolingRate: 0.5, Steps: 5, StagnationLimit: 2 },
+	}
+	anneal := addAnnealFlags(flag.NewFlagSet("tune", flag.ContinueOnError))
+	results, err := runTune(context.Background(), instances, configs, 2, 1, anneal, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatal("result:", results)
+	}
+	for _, result := range results {
+		// оптимум каждой задачи - 2 контейнера
+		if result.runs != 4 || result.mean() != 4 || result.variance() != 0 {
+			t.Error("result:", result)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := writeTune(&buffer, results, 1); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buffer.String()), "\n"); len(lines) != 5 ||
+		!strings.HasPrefix(lines[4], "Лучшие параметры: -T ") {
+		t.Error("result:\n" + buffer.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = runTune(ctx, instances, configs, 2, 1, anneal, 0)
+	if err != context.Canceled || len(results) != 0 {
+		t.Error("error:", err, "| result:", results)
+	}
+}